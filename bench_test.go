@@ -137,6 +137,55 @@ func BenchmarkCalcStats(b *testing.B) {
 	}
 }
 
+// BenchmarkCalcStatsOptions compares the default parser against parsers
+// with optional features enabled. The optional features are implemented
+// in a separate instantiation of the scanner, hence "default" and
+// "no_options" are expected to perform equally and mustn't be slower
+// than BenchmarkCalcStats on the same input.
+func BenchmarkCalcStatsOptions(b *testing.B) {
+	for _, bd := range []struct {
+		name  string
+		input SourceProvider
+	}{
+		{"small_336b____________", SrcFile("small_336b.json")},
+		{"nasa_SxSW_2016_125k___", SrcFile("nasa_SxSW_2016_125k.json.gz")},
+		{"array_nullbool_1024_5k", SrcFile("array_nullbool_1024_5k.json")},
+	} {
+		for _, po := range []struct {
+			name   string
+			parser func() *jscan.Parser[[]byte]
+		}{
+			{"default", func() *jscan.Parser[[]byte] {
+				return jscan.NewParser[[]byte](1024)
+			}},
+			{"no_options", func() *jscan.Parser[[]byte] {
+				return jscan.NewParserWithOptions[[]byte](1024, jscan.ParserOptions{})
+			}},
+			{"end_events", func() *jscan.Parser[[]byte] {
+				return jscan.NewParserWithOptions[[]byte](1024, jscan.ParserOptions{
+					EndEvents: true,
+				})
+			}},
+			{"track_pointer", func() *jscan.Parser[[]byte] {
+				return jscan.NewParserWithOptions[[]byte](1024, jscan.ParserOptions{
+					TrackPointer: true,
+				})
+			}},
+		} {
+			b.Run(bd.name+"/"+po.name, func(b *testing.B) {
+				src, err := bd.input.GetJSON()
+				require.NoError(b, err)
+
+				p := po.parser()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					gs = MustCalcStatsJscan(p, src)
+				}
+			})
+		}
+	}
+}
+
 func TestValid(t *testing.T) {
 	j := `[false,[[2, {"[foo]":[{"bar-baz":"fuz"}]}]]]`
 	require.True(t, json.Valid([]byte(j)))
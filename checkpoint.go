@@ -0,0 +1,267 @@
+package jscan
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/romshark/jscan/v2/internal/strfind"
+)
+
+// checkpointState defines where scanning resumes from a checkpoint.
+type checkpointState int8
+
+const (
+	_ checkpointState = iota
+
+	// checkpointStateAfterValue resumes after a value.
+	checkpointStateAfterValue
+
+	// checkpointStateObject resumes right after the opening curly bracket
+	// of the object on top of the stack.
+	checkpointStateObject
+
+	// checkpointStateArray resumes right after the opening square bracket
+	// of the array on top of the stack.
+	checkpointStateArray
+)
+
+// checkpointVersion is the version of the binary checkpoint encoding.
+const checkpointVersion = 1
+
+// ErrInvalidCheckpoint is returned by (*Checkpoint).UnmarshalBinary
+// when the data isn't a valid binary encoded checkpoint.
+var ErrInvalidCheckpoint = errors.New("invalid checkpoint")
+
+// Checkpoint is a saved scanning position created by (*Iterator).Checkpoint
+// which ResumeScan can continue scanning from.
+// Checkpoints are only valid for the source they were created for.
+// The zero value of Checkpoint refers to the beginning of the source.
+//
+// Checkpoint implements encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler for persistence.
+type Checkpoint struct {
+	offset int
+	state  checkpointState
+	stack  []stackNode
+}
+
+// Offset returns the index in the source scanning resumes at.
+func (c Checkpoint) Offset() int { return c.offset }
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c Checkpoint) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 16+len(c.stack)*16)
+	b = append(b, checkpointVersion, byte(c.state))
+	b = binary.AppendUvarint(b, uint64(c.offset))
+	b = binary.AppendUvarint(b, uint64(len(c.stack)))
+	for _, n := range c.stack {
+		b = append(b, byte(n.Type))
+		b = binary.AppendUvarint(b, uint64(n.Index))
+		b = binary.AppendUvarint(b, uint64(n.Len))
+		// Key indexes are -1 for non-members, hence offset by one.
+		b = binary.AppendUvarint(b, uint64(n.KeyIndex+1))
+		b = binary.AppendUvarint(b, uint64(n.KeyIndexEnd+1))
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// Returns ErrInvalidCheckpoint if b isn't a valid binary encoded checkpoint.
+func (c *Checkpoint) UnmarshalBinary(b []byte) error {
+	if len(b) < 2 || b[0] != checkpointVersion ||
+		b[1] > byte(checkpointStateArray) {
+		return ErrInvalidCheckpoint
+	}
+	state := checkpointState(b[1])
+	b = b[2:]
+	uvarint := func() int {
+		v, n := binary.Uvarint(b)
+		if n <= 0 || v > uint64(maxInt) {
+			b = nil
+			return -1
+		}
+		b = b[n:]
+		return int(v)
+	}
+	offset, l := uvarint(), uvarint()
+	if offset < 0 || l < 0 || l > len(b) {
+		return ErrInvalidCheckpoint
+	}
+	stack := make([]stackNode, l)
+	for j := range stack {
+		if len(b) < 1 {
+			return ErrInvalidCheckpoint
+		}
+		t := stackNodeType(b[0])
+		if t != stackNodeTypeObject && t != stackNodeTypeArray {
+			return ErrInvalidCheckpoint
+		}
+		b = b[1:]
+		stack[j] = stackNode{
+			Type:        t,
+			Index:       uvarint(),
+			Len:         uvarint(),
+			KeyIndex:    uvarint() - 1,
+			KeyIndexEnd: uvarint() - 1,
+		}
+		if b == nil {
+			return ErrInvalidCheckpoint
+		}
+	}
+	if len(b) > 0 {
+		return ErrInvalidCheckpoint
+	}
+	*c = Checkpoint{offset: offset, state: state, stack: stack}
+	return nil
+}
+
+// maxInt is the maximum value of int.
+const maxInt = int(^uint(0) >> 1)
+
+// Checkpoint returns a checkpoint which ResumeScan can continue scanning
+// from right after the current value. For objects and arrays scanning
+// continues with their first member or element value.
+// Checkpoint must only be called from within the callback of Scan,
+// (*Parser).Scan and their variants. Skipping children through
+// ActionSkipChildren or CaptureRaw doesn't affect the checkpoint.
+// When scanning a stream the checkpoint refers to the stream offset,
+// hence ResumeScan can continue scanning the entire input from it.
+func (i *Iterator[S]) Checkpoint() Checkpoint {
+	c := Checkpoint{
+		offset: i.offset + i.valueIndexEnd,
+		state:  checkpointStateAfterValue,
+		stack:  make([]stackNode, len(i.stack), len(i.stack)+1),
+	}
+	copy(c.stack, i.stack)
+	if i.end || (i.valueType != ValueTypeObject &&
+		i.valueType != ValueTypeArray) {
+		return c
+	}
+	var t stackNodeType = stackNodeTypeObject
+	c.state = checkpointStateObject
+	if i.valueType == ValueTypeArray {
+		t, c.state = stackNodeTypeArray, checkpointStateArray
+	}
+	c.offset = i.offset + i.valueIndex + 1
+	n := stackNode{
+		Type:        t,
+		KeyIndex:    i.keyIndex,
+		KeyIndexEnd: i.keyIndexEnd,
+		Index:       i.offset + i.valueIndex,
+	}
+	if n.KeyIndex != -1 {
+		n.KeyIndex += i.offset
+		n.KeyIndexEnd += i.offset
+	}
+	c.stack = append(c.stack, n)
+	return c
+}
+
+// ResumeScan is similar to Scan but continues scanning s from checkpoint c
+// previously created by (*Iterator).Checkpoint for the same s.
+// The callback is called for the value following the one c was created at
+// and Level, ArrayIndex, Key, Pointer and similar methods of the iterator
+// behave just as if s was scanned from the beginning.
+// Values preceding c are not validated.
+// Returns ErrorCodeInvalidCheckpoint if c doesn't fit s.
+//
+// Unlike (*Parser).ResumeScan this function will take an iterator instance
+// from a global iterator pool and can therefore be less efficient.
+// Consider reusing a Parser instance instead.
+//
+// WARNING: Don't use or alias *Iterator[S] after fn returns!
+func ResumeScan[S ~string | ~[]byte](
+	s S, c Checkpoint, fn func(*Iterator[S]) (err bool),
+) Error[S] {
+	var i *Iterator[S]
+	switch any(s).(type) {
+	case string:
+		x := iteratorPoolString.Get()
+		defer iteratorPoolString.Put(x)
+		i = x.(*Iterator[S])
+	case []byte:
+		x := iteratorPoolBytes.Get()
+		defer iteratorPoolBytes.Put(x)
+		i = x.(*Iterator[S])
+	default:
+		i = newIterator[S]()
+	}
+	return resumeScan(i, s, c, fn)
+}
+
+// ResumeScan is similar to (*Parser).Scan but continues scanning s
+// from checkpoint c. See ResumeScan for more details.
+//
+// WARNING: Don't use or alias *Iterator[S] after fn returns!
+func (p *Parser[S]) ResumeScan(
+	s S, c Checkpoint, fn func(*Iterator[S]) (err bool),
+) Error[S] {
+	return resumeScan(p.i, s, c, fn)
+}
+
+// resumeScan restores the state of checkpoint c in i and scans the rest of s.
+func resumeScan[S ~string | ~[]byte](
+	i *Iterator[S], s S, c Checkpoint, fn func(*Iterator[S]) (err bool),
+) Error[S] {
+	i.src = s
+	reset(i)
+	if !c.fits(len(s)) {
+		return Error[S]{Src: s, Index: 0, Code: ErrorCodeInvalidCheckpoint}
+	}
+	if c.state != 0 {
+		i.stack = append(i.stack, c.stack...)
+		i.resumeState, i.resumeOffset = c.state, c.offset
+		if i.trackPointer {
+			i.restorePointer()
+		}
+	}
+	t, err := scan(i, fn)
+	if err.IsErr() {
+		return err
+	}
+	var illegalChar bool
+	t, illegalChar = strfind.EndOfWhitespaceSeq(t)
+	if illegalChar {
+		return getError(ErrorCodeIllegalControlChar, s, t)
+	}
+	if len(t) > 0 {
+		return getError(ErrorCodeUnexpectedToken, s, t)
+	}
+	return Error[S]{}
+}
+
+// fits returns true if c can be a checkpoint of a source of length l.
+func (c Checkpoint) fits(l int) bool {
+	if c.state == 0 {
+		return c.offset == 0 && len(c.stack) == 0
+	}
+	if c.offset < 1 || c.offset > l {
+		return false
+	}
+	if c.state != checkpointStateAfterValue && len(c.stack) == 0 {
+		return false
+	}
+	for _, n := range c.stack {
+		if n.Index >= c.offset || n.Len < 0 {
+			return false
+		}
+		if n.KeyIndex == -1 {
+			continue
+		}
+		if n.KeyIndex < 0 || n.KeyIndexEnd-n.KeyIndex < 2 ||
+			n.KeyIndexEnd > n.Index {
+			return false
+		}
+	}
+	return true
+}
+
+// restorePointer rebuilds the tracked pointer of every object and array
+// on the stack.
+func (i *Iterator[S]) restorePointer() {
+	l := len(i.stack)
+	for j := 1; j <= l; j++ {
+		i.stack = i.stack[:j]
+		i.markPointer()
+	}
+}
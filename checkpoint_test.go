@@ -0,0 +1,163 @@
+package jscan_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeScan(t *testing.T) {
+	for _, input := range []string{
+		`42`,
+		`{}`,
+		`[]`,
+		` { "a" : [ 1 , { } , [ ] , { "b/c~" : null } ] , "d" : { "e" : [ [ true ] ] } } `,
+		`[[[]],{"x":{"y":[0,1,{"z":"w"}]}},"s",false]`,
+	} {
+		for _, o := range []jscan.ParserOptions{
+			{},
+			{EndEvents: true},
+			{TrackPointer: true},
+			{EndEvents: true, TrackPointer: true},
+		} {
+			t.Run(fmt.Sprintf("%+v %s", o, input), func(t *testing.T) {
+				testResumeScan(t, string(input), o)
+				testResumeScan(t, []byte(input), o)
+			})
+		}
+	}
+}
+
+func testResumeScan[S ~string | ~[]byte](
+	t *testing.T, input S, o jscan.ParserOptions,
+) {
+	type R struct {
+		Record
+		End bool
+		Len int
+	}
+	record := func(i *jscan.Iterator[S]) R {
+		return R{
+			Record: Record{
+				Level:      i.Level(),
+				ValueType:  i.ValueType(),
+				Key:        string(i.Key()),
+				Value:      string(i.Value()),
+				ArrayIndex: i.ArrayIndex(),
+				Pointer:    string(i.Pointer()),
+			},
+			End: i.IsEnd(),
+			Len: i.Len(),
+		}
+	}
+
+	p := jscan.NewParserWithOptions[S](64, o)
+	var expect []R
+	err := p.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+		expect = append(expect, record(i))
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+
+	for k := range expect {
+		// Scan up to and including the k-th value and create a checkpoint.
+		var c jscan.Checkpoint
+		var actual []R
+		n := 0
+		err := p.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+			actual = append(actual, record(i))
+			if n == k {
+				c = i.Checkpoint()
+				return true
+			}
+			n++
+			return false
+		})
+		require.Equal(t, jscan.ErrorCodeCallback, err.Code)
+
+		// Persist the checkpoint.
+		b, errMarshal := c.MarshalBinary()
+		require.NoError(t, errMarshal)
+		var restored jscan.Checkpoint
+		require.NoError(t, restored.UnmarshalBinary(b))
+		require.Equal(t, c, restored)
+
+		// Resume using a fresh parser.
+		p2 := jscan.NewParserWithOptions[S](64, o)
+		err = p2.ResumeScan(input, restored, func(i *jscan.Iterator[S]) (err bool) {
+			actual = append(actual, record(i))
+			return false
+		})
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+		require.Equal(t, expect, actual, "checkpoint at value %d", k)
+	}
+
+	// The zero checkpoint resumes from the beginning.
+	var actual []R
+	err = jscan.ResumeScan(input, jscan.Checkpoint{},
+		func(i *jscan.Iterator[S]) (err bool) {
+			if !o.EndEvents {
+				actual = append(actual, record(i))
+			}
+			return false
+		})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+	if !o.EndEvents {
+		require.Equal(t, expect, actual)
+	}
+}
+
+func TestResumeScanErr(t *testing.T) {
+	const input = `{"a":[1,2,x]}`
+	var c jscan.Checkpoint
+	err := jscan.Scan(input, func(i *jscan.Iterator[string]) (err bool) {
+		if i.ArrayIndex() == 0 {
+			c = i.Checkpoint()
+			return true
+		}
+		return false
+	})
+	require.Equal(t, jscan.ErrorCodeCallback, err.Code)
+
+	// Errors after the checkpoint are reported relative to the source.
+	err = jscan.ResumeScan(input, c, func(i *jscan.Iterator[string]) (err bool) {
+		return false
+	})
+	require.Equal(t, jscan.ErrorCodeUnexpectedToken, err.Code)
+	require.Equal(t, len(`{"a":[1,2,`), err.Index)
+
+	// The checkpoint doesn't fit a shorter source.
+	err = jscan.ResumeScan(`{}`, c, func(i *jscan.Iterator[string]) (err bool) {
+		return false
+	})
+	require.Equal(t, jscan.ErrorCodeInvalidCheckpoint, err.Code)
+	require.Equal(t, "error at index 0: invalid checkpoint", err.Error())
+}
+
+func TestCheckpointUnmarshalBinaryErr(t *testing.T) {
+	var c jscan.Checkpoint
+	err := jscan.Scan(`{"a":[{"b":[1]}]}`, func(i *jscan.Iterator[string]) (err bool) {
+		if i.Level() == 4 {
+			c = i.Checkpoint()
+		}
+		return false
+	})
+	require.False(t, err.IsErr())
+	b, err2 := c.MarshalBinary()
+	require.NoError(t, err2)
+
+	// Every truncation and any trailing data must be rejected.
+	for l := 0; l < len(b); l++ {
+		var x jscan.Checkpoint
+		require.ErrorIs(t, x.UnmarshalBinary(b[:l]), jscan.ErrInvalidCheckpoint)
+	}
+	var x jscan.Checkpoint
+	require.ErrorIs(t,
+		x.UnmarshalBinary(append(b[:len(b):len(b)], 0)), jscan.ErrInvalidCheckpoint)
+
+	// Unknown version.
+	b[0] = 0xff
+	require.ErrorIs(t, x.UnmarshalBinary(b), jscan.ErrInvalidCheckpoint)
+}
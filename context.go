@@ -0,0 +1,190 @@
+package jscan
+
+import (
+	"context"
+
+	"github.com/romshark/jscan/v2/internal/strfind"
+)
+
+// contextCheckInterval is the number of bytes of the source consumed
+// between two consecutive checks of the context.
+const contextCheckInterval = 64 * 1024
+
+// contextChecker periodically checks whether a context is done
+// and reports progress.
+type contextChecker struct {
+	done     <-chan struct{}
+	progress func(consumed int)
+	next     int
+	canceled bool
+}
+
+// check returns true if the context is done and reports progress
+// if the source was consumed past the next check.
+// check always returns false if c is nil.
+func (c *contextChecker) check(consumed int) (stop bool) {
+	return c != nil && consumed >= c.next && c.checkDone(consumed)
+}
+
+// checkDone schedules the next check, reports progress and
+// returns true if the context is done.
+func (c *contextChecker) checkDone(consumed int) (stop bool) {
+	c.next = consumed + contextCheckInterval
+	if c.progress != nil {
+		c.progress(consumed)
+	}
+	select {
+	case <-c.done:
+		c.canceled = true
+		return true
+	default:
+		return false
+	}
+}
+
+// ScanContext is similar to Scan but periodically checks whether ctx is done
+// and stops with ErrorCodeCanceled if it is. The context is checked
+// every time another 64 KiB of s were consumed and before every value
+// is passed to fn, which makes ScanContext slightly less efficient than Scan.
+// The returned error wraps ctx.Err() if ctx is done.
+// Objects and arrays skipped using CaptureRaw are interrupted too, but
+// strings and numbers longer than 64 KiB are only interrupted once they
+// were consumed, use ValidateContext to interrupt values of any length.
+//
+// Unlike (*Parser).ScanContext this function will take an iterator instance
+// from a global iterator pool and can therefore be less efficient.
+// Consider reusing a Parser instance instead.
+//
+// WARNING: Don't use or alias *Iterator[S] after fn returns!
+func ScanContext[S ~string | ~[]byte](
+	ctx context.Context, s S, fn func(*Iterator[S]) (err bool),
+) Error[S] {
+	var i *Iterator[S]
+	switch any(s).(type) {
+	case string:
+		x := iteratorPoolString.Get()
+		defer iteratorPoolString.Put(x)
+		i = x.(*Iterator[S])
+	case []byte:
+		x := iteratorPoolBytes.Get()
+		defer iteratorPoolBytes.Put(x)
+		i = x.(*Iterator[S])
+	default:
+		i = newIterator[S]()
+	}
+	return scanContext(ctx, i, s, fn)
+}
+
+// ScanContext is similar to (*Parser).Scan but periodically checks
+// whether ctx is done and reports progress to ParserOptions.Progress.
+// See ScanContext for more details.
+//
+// WARNING: Don't use or alias *Iterator[S] after fn returns!
+func (p *Parser[S]) ScanContext(
+	ctx context.Context, s S, fn func(*Iterator[S]) (err bool),
+) Error[S] {
+	return scanContext(ctx, p.i, s, fn)
+}
+
+// ValidateContext is similar to Validate but periodically checks whether
+// ctx is done and stops with ErrorCodeCanceled if it is. The context is
+// checked every time another 64 KiB of s were consumed, even within long
+// strings, numbers and whitespace sequences, which makes ValidateContext
+// slightly less efficient than Validate. The returned error wraps
+// ctx.Err() if ctx is done.
+//
+// Unlike (*Validator).ValidateContext this function will take a validator
+// instance from a global pool and can therefore be less efficient.
+// Consider reusing a Validator instance instead.
+func ValidateContext[S ~string | ~[]byte](ctx context.Context, s S) Error[S] {
+	var v *Validator[S]
+	switch any(s).(type) {
+	case string:
+		x := validatorPoolString.Get()
+		defer validatorPoolString.Put(x)
+		v = x.(*Validator[S])
+	case []byte:
+		x := validatorPoolBytes.Get()
+		defer validatorPoolBytes.Put(x)
+		v = x.(*Validator[S])
+	default:
+		v = newValidator[S]()
+	}
+	v.stack = v.stack[:0]
+	return validateContext(ctx, v.stack, s, 0, nil)
+}
+
+// ValidateContext is similar to (*Validator).Validate but periodically
+// checks whether ctx is done and reports progress to
+// ValidatorOptions.Progress. See ValidateContext for more details.
+func (v *Validator[S]) ValidateContext(ctx context.Context, s S) Error[S] {
+	return validateContext(ctx, v.stack, s, v.numbers, v.progress)
+}
+
+// validateContext validates s in windows of contextCheckInterval bytes
+// checking ctx after every window.
+func validateContext[S ~string | ~[]byte](
+	ctx context.Context, st []stackNodeType, s S,
+	numbers NumberType, progress func(consumed int),
+) Error[S] {
+	c := contextChecker{done: ctx.Done(), progress: progress}
+	var v windowValidator
+	v.reset(st, false, numbers)
+	for end := 0; ; {
+		r := end
+		end = min(len(s), end+contextCheckInterval)
+		if _, err := validateWindow(&v, s[:end], r, 0, end == len(s)); err.IsErr() {
+			err.Src = s
+			return err
+		}
+		if end == len(s) {
+			break
+		}
+		if c.checkDone(end) {
+			err := getError(ErrorCodeCanceled, s, s[end:])
+			err.err = ctx.Err()
+			return err
+		}
+	}
+	if progress != nil {
+		progress(len(s))
+	}
+	return Error[S]{}
+}
+
+// scanContext scans s calling fn for every value and checks ctx
+// every contextCheckInterval bytes.
+func scanContext[S ~string | ~[]byte](
+	ctx context.Context, i *Iterator[S], s S, fn func(*Iterator[S]) (err bool),
+) Error[S] {
+	reset(i)
+	i.src = s
+
+	c := contextChecker{done: ctx.Done(), progress: i.progress}
+	i.check = &c
+	t, err := scan(i, func(i *Iterator[S]) (err bool) {
+		if c.check(i.valueIndex) {
+			return true
+		}
+		return fn(i)
+	})
+	i.check = nil
+	if err.IsErr() {
+		if c.canceled {
+			err.Code, err.err = ErrorCodeCanceled, ctx.Err()
+		}
+		return err
+	}
+	var illegalChar bool
+	t, illegalChar = strfind.EndOfWhitespaceSeq(t)
+	if illegalChar {
+		return getError(ErrorCodeIllegalControlChar, s, t)
+	}
+	if len(t) > 0 {
+		return getError(ErrorCodeUnexpectedToken, s, t)
+	}
+	if i.progress != nil {
+		i.progress(len(s))
+	}
+	return Error[S]{}
+}
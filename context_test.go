@@ -0,0 +1,218 @@
+package jscan_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// largeArray returns a JSON array of n objects.
+func largeArray(n int) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for j := 0; j < n; j++ {
+		if j > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(`{"id":12345,"name":"some name","tags":["a","b"]}`)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+func TestScanContext(t *testing.T) {
+	input := largeArray(20_000)
+
+	t.Run("completes", func(t *testing.T) {
+		var values, valuesCtx int
+		require.False(t, jscan.Scan(input, func(*jscan.Iterator[string]) bool {
+			values++
+			return false
+		}).IsErr())
+		err := jscan.ScanContext(context.Background(), input,
+			func(*jscan.Iterator[string]) bool {
+				valuesCtx++
+				return false
+			})
+		require.False(t, err.IsErr())
+		require.Equal(t, values, valuesCtx)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		err := jscan.ScanContext(ctx, []byte(input),
+			func(i *jscan.Iterator[[]byte]) bool {
+				if i.ValueIndex() > len(input)/2 {
+					cancel()
+				}
+				return false
+			})
+		require.True(t, err.IsErr())
+		require.Equal(t, jscan.ErrorCodeCanceled, err.Code)
+		require.Greater(t, err.Index, len(input)/2)
+		require.Less(t, err.Index, len(input))
+		require.Contains(t, err.Error(), "canceled")
+		require.True(t, errors.Is(err, context.Canceled))
+	})
+
+	t.Run("canceled_before", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var called bool
+		err := jscan.ScanContext(ctx, input, func(*jscan.Iterator[string]) bool {
+			called = true
+			return false
+		})
+		require.Equal(t, jscan.ErrorCodeCanceled, err.Code)
+		require.Equal(t, 0, err.Index)
+		require.False(t, called)
+	})
+
+	t.Run("canceled_skipped", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var raw []string
+		err := jscan.ScanContext(ctx, input, func(i *jscan.Iterator[string]) bool {
+			cancel()
+			raw = append(raw, i.CaptureRaw())
+			return false
+		})
+		require.Equal(t, []string{""}, raw)
+		require.Equal(t, jscan.ErrorCodeCanceled, err.Code)
+		require.Greater(t, err.Index, 0)
+		require.Less(t, err.Index, len(input))
+		require.True(t, errors.Is(err, context.Canceled))
+	})
+
+	t.Run("callback_error", func(t *testing.T) {
+		err := jscan.ScanContext(context.Background(), input,
+			func(*jscan.Iterator[string]) bool { return true })
+		require.Equal(t, jscan.ErrorCodeCallback, err.Code)
+	})
+
+	t.Run("progress", func(t *testing.T) {
+		var reported []int
+		p := jscan.NewParserWithOptions[string](64, jscan.ParserOptions{
+			Progress: func(consumed int) { reported = append(reported, consumed) },
+		})
+		err := p.ScanContext(context.Background(), input,
+			func(*jscan.Iterator[string]) bool { return false })
+		require.False(t, err.IsErr())
+		require.Greater(t, len(reported), 2)
+		require.Equal(t, len(input), reported[len(reported)-1])
+		for j := 1; j < len(reported); j++ {
+			require.Greater(t, reported[j], reported[j-1])
+		}
+	})
+}
+
+func TestValidateContext(t *testing.T) {
+	input := largeArray(20_000)
+
+	t.Run("valid", func(t *testing.T) {
+		require.False(t, jscan.ValidateContext(context.Background(), input).IsErr())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{``, `[1,]`, `{} x`, "\"\x00\""} {
+			expect := jscan.Validate(input)
+			err := jscan.ValidateContext(context.Background(), input)
+			require.Equal(t, expect, err, "input: %q", input)
+		}
+	})
+
+	t.Run("invalid_across_windows", func(t *testing.T) {
+		// Place the invalid values right before the end of
+		// the first 64 KiB window so they continue in the next one.
+		const window = 64 * 1024
+		for _, value := range []string{
+			`"\u12x4"`, `"ab\`, `-1.e5`, `1.5e+`, `truex`, `nulL`, `"a` + "\x01",
+			`{"a" 1}`, `[1}`,
+		} {
+			for shift := 1; shift < len(value); shift++ {
+				input := "[" + strings.Repeat(" ", window-1-shift) + value
+				expect := jscan.Validate(input)
+				require.True(t, expect.IsErr(), "input: %q", value)
+				err := jscan.ValidateContext(context.Background(), input)
+				require.Equal(t, expect, err, "value: %q, shift: %d", value, shift)
+			}
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := jscan.ValidateContext(ctx, input)
+		require.Equal(t, jscan.ErrorCodeCanceled, err.Code)
+	})
+
+	t.Run("validator", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var last int
+		v := jscan.NewValidatorWithOptions[string](64, jscan.ValidatorOptions{
+			Numbers: jscan.NumberTypeInt64,
+			Progress: func(consumed int) {
+				if last = consumed; consumed > len(input)/2 {
+					cancel()
+				}
+			},
+		})
+		err := v.ValidateContext(ctx, input)
+		require.Equal(t, jscan.ErrorCodeCanceled, err.Code)
+		require.Greater(t, last, len(input)/2)
+
+		err = v.ValidateContext(context.Background(), `[1, 1.5]`)
+		require.Equal(t, jscan.ErrorCodeNumberNotRepresentable, err.Code)
+		require.Equal(t, 4, err.Index)
+
+		require.False(t, v.ValidateContext(context.Background(), `[1,2]`).IsErr())
+		require.Equal(t, 5, last)
+	})
+}
+
+func TestValidateContextLongValues(t *testing.T) {
+	const size = 1024 * 1024
+	for _, td := range []struct {
+		name, input string
+	}{
+		{"string", `["` + strings.Repeat("a", size) + `"]`},
+		{"escaped", `{"` + strings.Repeat(`\n`, size/2) + `":0}`},
+		{"number", `[1` + strings.Repeat("0", size) + `]`},
+		{"whitespace", `[` + strings.Repeat(" ", size) + `]`},
+	} {
+		t.Run(td.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			v := jscan.NewValidatorWithOptions[string](64, jscan.ValidatorOptions{
+				Progress: func(consumed int) {
+					if consumed > len(td.input)/2 {
+						cancel()
+					}
+				},
+			})
+			err := v.ValidateContext(ctx, td.input)
+			require.Equal(t, jscan.ErrorCodeCanceled, err.Code)
+			require.True(t, errors.Is(err, context.Canceled))
+			// Validation must be interrupted within the value.
+			require.Greater(t, err.Index, len(td.input)/2)
+			require.Less(t, err.Index, len(td.input)-2)
+		})
+	}
+}
+
+func TestValidateContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+	err := jscan.ValidateContext(ctx, largeArray(20_000))
+	require.Equal(t, jscan.ErrorCodeCanceled, err.Code)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.False(t, errors.Is(err, context.Canceled))
+	require.True(t, strings.HasSuffix(err.Error(), ": canceled: context deadline exceeded"))
+}
@@ -0,0 +1,324 @@
+package jscan
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/romshark/jscan/v2/internal/jsonnum"
+)
+
+// Decimal is the exact value of a JSON number in normalized decimal form:
+// (-1)^Neg × Digits × 10^Exp, where Digits has neither leading nor
+// trailing zeros. Zero is represented by empty Digits and a zero Exp.
+// For example, 12.50 is represented by Digits "125" and Exp -1
+// while 1.2e3 is represented by Digits "12" and Exp 2.
+type Decimal struct {
+	Neg    bool
+	Digits string
+	Exp    int
+}
+
+// String returns the canonical representation of d, such as "-125e-1".
+// Numerically equal numbers have the same canonical representation,
+// except for negative zero which is "-0".
+func (d Decimal) String() string {
+	b := make([]byte, 0, len(d.Digits)+8)
+	if d.Neg {
+		b = append(b, '-')
+	}
+	if d.Digits == "" {
+		return string(append(b, '0'))
+	}
+	b = append(b, d.Digits...)
+	if d.Exp != 0 {
+		b = append(b, 'e')
+		b = strconv.AppendInt(b, int64(d.Exp), 10)
+	}
+	return string(b)
+}
+
+// IsInteger returns true if d has no fractional part.
+func (d Decimal) IsInteger() bool { return d.Exp >= 0 }
+
+// NumberType is a Go numeric type JSON numbers can be checked against
+// for exact representability, see (Decimal).Fits and ValidatorOptions.
+type NumberType int8
+
+// Go numeric types
+const (
+	_ NumberType = iota
+	NumberTypeInt64
+	NumberTypeUint64
+	NumberTypeFloat64
+)
+
+func (t NumberType) String() string {
+	switch t {
+	case NumberTypeInt64:
+		return "int64"
+	case NumberTypeUint64:
+		return "uint64"
+	case NumberTypeFloat64:
+		return "float64"
+	}
+	return ""
+}
+
+// Fits returns true if the value of d is exactly representable
+// by the Go numeric type t regardless of its notation,
+// for example 1e3 fits int64 while 0.1 doesn't fit float64.
+// Negative zero fits all types.
+func (d Decimal) Fits(t NumberType) bool {
+	if d.Digits == "" {
+		return t != 0
+	}
+	switch t {
+	case NumberTypeInt64:
+		n, ok := d.uint64()
+		if !ok {
+			return false
+		}
+		if d.Neg {
+			return n <= 1<<63
+		}
+		return n <= math.MaxInt64
+	case NumberTypeUint64:
+		_, ok := d.uint64()
+		return ok && !d.Neg
+	case NumberTypeFloat64:
+		return d.fitsFloat64()
+	}
+	return false
+}
+
+// uint64 returns the absolute value of d if it's an integer
+// that fits into uint64.
+func (d Decimal) uint64() (n uint64, ok bool) {
+	if d.Exp < 0 || len(d.Digits)+d.Exp > 20 {
+		return 0, false
+	}
+	for j := 0; j < len(d.Digits)+d.Exp; j++ {
+		var c uint64
+		if j < len(d.Digits) {
+			c = uint64(d.Digits[j] - '0')
+		}
+		if n > (math.MaxUint64-c)/10 {
+			return 0, false
+		}
+		n = n*10 + c
+	}
+	return n, true
+}
+
+func (d Decimal) fitsFloat64() bool {
+	// The magnitude of exactly representable non-zero values lies within
+	// [4.9e-324, 1.8e308] and their decimal expansion never has more
+	// than 767 significant digits. Checking these bounds first prevents
+	// excessive memory usage by big.Rat.
+	if m := len(d.Digits) + d.Exp; m > 309 || m < -323 || len(d.Digits) > 767 {
+		return false
+	}
+	var r big.Rat
+	if _, ok := r.SetString(d.Digits + "e" + strconv.Itoa(d.Exp)); !ok {
+		return false
+	}
+	_, exact := r.Float64()
+	return exact
+}
+
+// ParseDecimal parses the JSON number s into its exact decimal value.
+// Returns ErrNotNumber if s isn't a single valid JSON number and
+// ErrNumberOverflow if the decimal exponent doesn't fit into int32.
+func ParseDecimal[S ~string | ~[]byte](s S) (Decimal, error) {
+	if len(s) < 1 {
+		return Decimal{}, ErrNotNumber
+	}
+	if t, rc := jsonnum.ReadNumber(s); rc == jsonnum.ReturnCodeErr || len(t) > 0 {
+		return Decimal{}, ErrNotNumber
+	}
+	return parseDecimal(s)
+}
+
+// Decimal returns the exact decimal value of the number value.
+// Returns ErrNotNumber if the value isn't a number and
+// ErrNumberOverflow if the decimal exponent doesn't fit into int32.
+func (i *Iterator[S]) Decimal() (Decimal, error) {
+	if i.valueType != ValueTypeNumber {
+		return Decimal{}, ErrNotNumber
+	}
+	return parseDecimal(i.src[i.valueIndex:i.valueIndexEnd])
+}
+
+// BigInt returns the number value as big.Int.
+// Returns ErrNotNumber if the value isn't a number and ErrNumberNotInteger
+// if the number has a fraction or an exponent part just like Int64.
+// Use Decimal for numbers in any notation.
+func (i *Iterator[S]) BigInt() (*big.Int, error) {
+	if i.valueType != ValueTypeNumber {
+		return nil, ErrNotNumber
+	}
+	if i.numberKind != jsonnum.ReturnCodeInteger {
+		return nil, ErrNumberNotInteger
+	}
+	v := i.src[i.valueIndex:i.valueIndexEnd]
+	if len(v) <= 18 {
+		n, _ := i.Int64()
+		return big.NewInt(n), nil
+	}
+	n, _ := new(big.Int).SetString(string(v), 10)
+	return n, nil
+}
+
+// BigFloat returns the number value as big.Float rounded to the nearest
+// even value. The precision is chosen such that all significant digits
+// of the number are preserved, but no less than 64 bits.
+// Returns ErrNotNumber if the value isn't a number and ErrNumberOverflow
+// if the number is out of range of big.Float.
+func (i *Iterator[S]) BigFloat() (*big.Float, error) {
+	if i.valueType != ValueTypeNumber {
+		return nil, ErrNotNumber
+	}
+	v := i.src[i.valueIndex:i.valueIndexEnd]
+	// log2(10) < 3.33 bits per decimal digit.
+	prec := uint(significantDigits(v))*333/100 + 1
+	if prec < 64 {
+		prec = 64
+	}
+	f, _, err := new(big.Float).SetPrec(prec).SetMode(big.ToNearestEven).
+		Parse(string(v), 10)
+	if err != nil {
+		return nil, ErrNumberOverflow
+	}
+	if f.IsInf() {
+		return nil, ErrNumberOverflow
+	}
+	return f, nil
+}
+
+// significantDigits returns the number of digits of the valid JSON number s
+// excluding leading and trailing zeros, which equals len(Decimal.Digits).
+func significantDigits[S ~string | ~[]byte](s S) int {
+	first, last, j := -1, -1, 0
+	for ; j < len(s) && s[j] != 'e' && s[j] != 'E'; j++ {
+		if s[j] > '0' && s[j] <= '9' {
+			if first == -1 {
+				first = j
+			}
+			last = j
+		}
+	}
+	if first == -1 {
+		return 0
+	}
+	n := last - first + 1
+	for j = first; j < last; j++ {
+		if s[j] == '.' {
+			n--
+			break
+		}
+	}
+	return n
+}
+
+// numberFits returns true if the number value is exactly
+// representable by t, see (Decimal).Fits.
+func (i *Iterator[S]) numberFits(t NumberType) bool {
+	return numberFits(
+		i.src[i.valueIndex:i.valueIndexEnd],
+		i.numberKind == jsonnum.ReturnCodeInteger, t,
+	)
+}
+
+// numberFits returns true if the valid JSON number s is exactly
+// representable by t. integer must be true if s is in integer notation.
+func numberFits[S ~string | ~[]byte](s S, integer bool, t NumberType) bool {
+	if integer {
+		// Avoid allocating a Decimal for integers.
+		switch t {
+		case NumberTypeInt64:
+			neg, n, err := parseInteger(s)
+			if neg {
+				return err == nil && n <= 1<<63
+			}
+			return err == nil && n <= math.MaxInt64
+		case NumberTypeUint64:
+			neg, n, err := parseInteger(s)
+			return err == nil && (!neg || n == 0)
+		case NumberTypeFloat64:
+			if d := len(s); d <= 15 || (s[0] == '-' && d <= 16) {
+				return true
+			}
+		}
+	}
+	d, err := parseDecimal(s)
+	return err == nil && d.Fits(t)
+}
+
+// parseDecimal parses the valid JSON number s.
+func parseDecimal[S ~string | ~[]byte](s S) (d Decimal, err error) {
+	if s[0] == '-' {
+		d.Neg, s = true, s[1:]
+	}
+
+	// Split the number into its integer, fraction and exponent parts.
+	j := 0
+	for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+		j++
+	}
+	integer, s := s[:j], s[j:]
+	var fraction S
+	if len(s) > 0 && s[0] == '.' {
+		j = 1
+		for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			j++
+		}
+		fraction, s = s[1:j], s[j:]
+	}
+	var exp int64
+	if len(s) > 0 { // Exponent
+		s = s[1:]
+		neg := s[0] == '-'
+		if s[0] == '-' || s[0] == '+' {
+			s = s[1:]
+		}
+		for j = 0; j < len(s); j++ {
+			if exp = exp*10 + int64(s[j]-'0'); exp > math.MaxInt32*2 {
+				return Decimal{}, ErrNumberOverflow
+			}
+		}
+		if neg {
+			exp = -exp
+		}
+	}
+	exp -= int64(len(fraction))
+
+	// Strip leading and trailing zeros.
+	for len(integer) > 0 && integer[0] == '0' {
+		integer = integer[1:]
+	}
+	if len(integer) == 0 {
+		for len(fraction) > 0 && fraction[0] == '0' {
+			fraction = fraction[1:]
+		}
+	}
+	for len(fraction) > 0 && fraction[len(fraction)-1] == '0' {
+		fraction, exp = fraction[:len(fraction)-1], exp+1
+	}
+	if len(fraction) == 0 {
+		for len(integer) > 0 && integer[len(integer)-1] == '0' {
+			integer, exp = integer[:len(integer)-1], exp+1
+		}
+	}
+	if len(integer)+len(fraction) == 0 {
+		return Decimal{Neg: d.Neg}, nil
+	}
+	if exp < math.MinInt32 || exp > math.MaxInt32 {
+		return Decimal{}, ErrNumberOverflow
+	}
+	b := make([]byte, 0, len(integer)+len(fraction))
+	b = append(b, integer...)
+	b = append(b, fraction...)
+	d.Digits, d.Exp = string(b), int(exp)
+	return d, nil
+}
@@ -0,0 +1,211 @@
+package jscan_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for _, td := range []struct {
+		input  string
+		expect jscan.Decimal
+		str    string
+	}{
+		{`0`, jscan.Decimal{}, "0"},
+		{`-0`, jscan.Decimal{Neg: true}, "-0"},
+		{`0.000e-5`, jscan.Decimal{}, "0"},
+		{`42`, jscan.Decimal{Digits: "42"}, "42"},
+		{`-4200`, jscan.Decimal{Neg: true, Digits: "42", Exp: 2}, "-42e2"},
+		{`12.50`, jscan.Decimal{Digits: "125", Exp: -1}, "125e-1"},
+		{`0.0012`, jscan.Decimal{Digits: "12", Exp: -4}, "12e-4"},
+		{`10.5`, jscan.Decimal{Digits: "105", Exp: -1}, "105e-1"},
+		{`100.00`, jscan.Decimal{Digits: "1", Exp: 2}, "1e2"},
+		{`1.2e3`, jscan.Decimal{Digits: "12", Exp: 2}, "12e2"},
+		{`1.2E+3`, jscan.Decimal{Digits: "12", Exp: 2}, "12e2"},
+		{`-120e-3`, jscan.Decimal{Neg: true, Digits: "12", Exp: -2}, "-12e-2"},
+		{
+			`123456789012345678901234567890.123456789`,
+			jscan.Decimal{Digits: "123456789012345678901234567890123456789", Exp: -9},
+			"123456789012345678901234567890123456789e-9",
+		},
+		{`1e2147483647`, jscan.Decimal{Digits: "1", Exp: 2147483647}, "1e2147483647"},
+	} {
+		t.Run(td.input, func(t *testing.T) {
+			d, err := jscan.ParseDecimal(td.input)
+			require.NoError(t, err)
+			require.Equal(t, td.expect, d)
+			require.Equal(t, td.str, d.String())
+
+			d, err = jscan.ParseDecimal([]byte(td.input))
+			require.NoError(t, err)
+			require.Equal(t, td.expect, d)
+		})
+	}
+
+	for _, input := range []string{``, `-`, `01`, `1.`, `1e`, `1 `, `"1"`, `1,2`} {
+		_, err := jscan.ParseDecimal(input)
+		require.ErrorIs(t, err, jscan.ErrNotNumber, "input: %q", input)
+	}
+	for _, input := range []string{`1e2147483648`, `1e-99999999999999999999`} {
+		_, err := jscan.ParseDecimal(input)
+		require.ErrorIs(t, err, jscan.ErrNumberOverflow, "input: %q", input)
+	}
+}
+
+func TestDecimalFits(t *testing.T) {
+	const (
+		I = jscan.NumberTypeInt64
+		U = jscan.NumberTypeUint64
+		F = jscan.NumberTypeFloat64
+	)
+	for _, td := range []struct {
+		input  string
+		expect []jscan.NumberType
+	}{
+		{`0`, []jscan.NumberType{I, U, F}},
+		{`-0.0`, []jscan.NumberType{I, U, F}},
+		{`1e3`, []jscan.NumberType{I, U, F}},
+		{`-1.0`, []jscan.NumberType{I, F}},
+		{`0.5`, []jscan.NumberType{F}},
+		{`0.1`, nil},
+		{`9223372036854775807`, []jscan.NumberType{I, U}},
+		{`-9223372036854775808`, []jscan.NumberType{I, F}},
+		{`9223372036854775808`, []jscan.NumberType{U, F}},
+		{`18446744073709551615`, []jscan.NumberType{U}},
+		{`18446744073709551616`, []jscan.NumberType{F}},
+		{`9007199254740993`, []jscan.NumberType{I, U}},
+		{`1e22`, []jscan.NumberType{F}},
+		{`1e23`, nil},
+		{`1.7976931348623157e308`, nil},
+		{`9007199254740992e10`, []jscan.NumberType{F}},
+		{`1e309`, nil},
+		{`4.9406564584124654e-324`, nil},
+		{`1e400`, nil},
+		{`1e-400`, nil},
+	} {
+		t.Run(td.input, func(t *testing.T) {
+			d, err := jscan.ParseDecimal(td.input)
+			require.NoError(t, err)
+			var actual []jscan.NumberType
+			for _, nt := range []jscan.NumberType{I, U, F} {
+				if d.Fits(nt) {
+					actual = append(actual, nt)
+				}
+			}
+			require.Equal(t, td.expect, actual)
+			require.False(t, d.Fits(0))
+		})
+	}
+}
+
+func TestBigNumbers(t *testing.T) {
+	const input = `[` +
+		`123456789012345678901234567890,` +
+		`-42,` +
+		`0.1,` +
+		`1.5e-3,` +
+		`123456789012345678901234567890.123456789012345678901234567890,` +
+		`-0.000123456789012345678901234567890000e5,` +
+		`"x"` +
+		`]`
+	testBigNumbers(t, string(input))
+	testBigNumbers(t, []byte(input))
+}
+
+func testBigNumbers[S ~string | ~[]byte](t *testing.T, input S) {
+	t.Run(testDataType(input), func(t *testing.T) {
+		type R struct {
+			BigInt, BigFloat  string
+			BigIntErr, FltErr error
+		}
+		var actual []R
+		err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+			if i.Level() < 1 {
+				return false
+			}
+			var r R
+			var n *big.Int
+			var f *big.Float
+			if n, r.BigIntErr = i.BigInt(); n != nil {
+				r.BigInt = n.String()
+			}
+			if f, r.FltErr = i.BigFloat(); f != nil {
+				r.BigFloat = f.Text('g', -1)
+			}
+			actual = append(actual, r)
+			return false
+		})
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+		require.Equal(t, []R{
+			{
+				BigInt:   "123456789012345678901234567890",
+				BigFloat: "1.2345678901234567890123456789e+29",
+			},
+			{BigInt: "-42", BigFloat: "-42"},
+			{BigIntErr: jscan.ErrNumberNotInteger, BigFloat: "0.1"},
+			{BigIntErr: jscan.ErrNumberNotInteger, BigFloat: "0.0015"},
+			{
+				BigIntErr: jscan.ErrNumberNotInteger,
+				BigFloat:  "1.2345678901234567890123456789012345678901234567890123456789e+29",
+			},
+			{
+				BigIntErr: jscan.ErrNumberNotInteger,
+				BigFloat:  "-12.345678901234567890123456789",
+			},
+			{BigIntErr: jscan.ErrNotNumber, FltErr: jscan.ErrNotNumber},
+		}, actual)
+	})
+}
+
+func TestValidatorNumbers(t *testing.T) {
+	for _, td := range []struct {
+		numbers jscan.NumberType
+		input   string
+		index   int // -1 for valid input
+	}{
+		{jscan.NumberTypeInt64, `[1, -2, 1e3, 3.0, "1.5"]`, -1},
+		{jscan.NumberTypeInt64, `{"a": [1, 1.5]}`, len(`{"a": [1, `)},
+		{jscan.NumberTypeInt64, `9223372036854775808`, 0},
+		{jscan.NumberTypeUint64, `[18446744073709551615, -0]`, -1},
+		{jscan.NumberTypeUint64, `[1, -1]`, len(`[1, `)},
+		{jscan.NumberTypeFloat64, `[0.5, 0.25, 9007199254740992, 1e22]`, -1},
+		{jscan.NumberTypeFloat64, `[0.5, 0.1]`, len(`[0.5, `)},
+		{jscan.NumberTypeFloat64, `[9007199254740993]`, len(`[`)},
+	} {
+		t.Run(td.numbers.String()+" "+td.input, func(t *testing.T) {
+			v := jscan.NewValidatorWithOptions[string](64, jscan.ValidatorOptions{
+				Numbers: td.numbers,
+			})
+			err := v.Validate(td.input)
+			if td.index == -1 {
+				require.False(t, err.IsErr(), "unexpected error: %s", err)
+				require.True(t, v.Valid(td.input))
+				// Without constraint the input must also be valid.
+				require.False(t, jscan.Validate(td.input).IsErr())
+				return
+			}
+			require.Equal(t, jscan.ErrorCodeNumberNotRepresentable, err.Code)
+			require.Equal(t, td.index, err.Index)
+			require.Contains(t, err.Error(), "number not representable")
+			require.False(t, v.Valid(td.input))
+			require.False(t, jscan.Validate(td.input).IsErr())
+
+			vb := jscan.NewValidatorWithOptions[[]byte](64, jscan.ValidatorOptions{
+				Numbers: td.numbers,
+			})
+			_, errb := vb.ValidateOne([]byte(td.input))
+			require.Equal(t, jscan.ErrorCodeNumberNotRepresentable, errb.Code)
+			require.Equal(t, td.index, errb.Index)
+		})
+	}
+
+	// Syntax errors take precedence over representability.
+	v := jscan.NewValidatorWithOptions[string](64, jscan.ValidatorOptions{
+		Numbers: jscan.NumberTypeInt64,
+	})
+	require.Equal(t, jscan.ErrorCodeUnexpectedToken, v.Validate(`[1] x`).Code)
+	require.Equal(t, jscan.ErrorCodeMalformedNumber, v.Validate(`[1.]`).Code)
+}
@@ -236,3 +236,37 @@ func ExampleScan_decode2DIntArray() {
 	// Output:
 	// [[1 2 34 567] [8901 2147483647 -1 42]]
 }
+
+func ExampleOnDemand() {
+	doc := jscan.OnDemand(`{
+		"user": {"id": 42, "name": "Alice"},
+		"items": [{"price": 1.5}, {"price": 2.25}]
+	}`)
+
+	id, err := doc.Field("user").Field("id").Int64()
+	if err != nil {
+		fmt.Printf("ERR: %v\n", err)
+		return
+	}
+	fmt.Println("id:", id)
+
+	var total float64
+	err = doc.Field("items").Each(func(v jscan.Value[string]) (err bool) {
+		p, e := v.Field("price").Float64()
+		total += p
+		return e != nil
+	})
+	if err != nil {
+		fmt.Printf("ERR: %v\n", err)
+		return
+	}
+	fmt.Println("total:", total)
+
+	if err := doc.End(); err != nil {
+		fmt.Printf("ERR: %v\n", err)
+	}
+
+	// Output:
+	// id: 42
+	// total: 3.75
+}
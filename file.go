@@ -0,0 +1,112 @@
+package jscan
+
+import (
+	"io"
+	"math"
+	"os"
+	"slices"
+	"unicode/utf8"
+)
+
+// ScanFile is similar to Scan but scans the file at path.
+// On Linux the file is memory-mapped read-only and scanned directly.
+// If the file can't be mapped, it's read into a pooled buffer instead.
+// Errors are of type *os.PathError and wrap an Error[[]byte]
+// if the file is invalid JSON.
+//
+// This function will take an iterator instance from a global iterator pool.
+//
+// WARNING: Don't use or alias *Iterator[[]byte] after fn returns!
+// Key and Value in particular refer to the mapping or the buffer, which
+// are released when ScanFile returns. Modifying the file during the scan
+// may crash the program.
+func ScanFile(path string, fn func(*Iterator[[]byte]) (err bool)) error {
+	return processFile(path, "scan", func(b []byte) Error[[]byte] {
+		return Scan(b, fn)
+	})
+}
+
+// ValidateFile is similar to Validate but validates the file at path.
+// See ScanFile for more details.
+func ValidateFile(path string) error {
+	return processFile(path, "validate", Validate[[]byte])
+}
+
+// processFile opens the file at path and calls fn with either the mapping
+// of the file or, if the file can't be mapped, its contents read into
+// a pooled buffer. Errors are wrapped in *os.PathError with operation op.
+func processFile(
+	path, op string, fn func([]byte) Error[[]byte],
+) (err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := 0
+	if fi.Mode().IsRegular() && fi.Size() <= math.MaxInt {
+		size = int(fi.Size())
+	}
+	if size > 0 {
+		if b, err := mmap(f, size); err == nil {
+			defer func() {
+				if errUnmap := munmap(b); errUnmap != nil && err == nil {
+					err = &os.PathError{Op: "munmap", Path: path, Err: errUnmap}
+				}
+			}()
+			if e := fn(b); e.IsErr() {
+				return &os.PathError{Op: op, Path: path, Err: detachError(e)}
+			}
+			return nil
+		}
+	}
+
+	buf := filePool.Get().(*[]byte)
+	defer filePool.Put(buf)
+	*buf, err = readFile(f, (*buf)[:0], size)
+	if err != nil {
+		return &os.PathError{Op: "read", Path: path, Err: err}
+	}
+	if e := fn(*buf); e.IsErr() {
+		return &os.PathError{Op: op, Path: path, Err: detachError(e)}
+	}
+	return nil
+}
+
+// readFile appends the contents of f to b. size is the expected size
+// of the file or 0 if unknown.
+func readFile(f *os.File, b []byte, size int) ([]byte, error) {
+	// Reserve an extra byte to read EOF without growing b.
+	b = slices.Grow(b, size+1)
+	for {
+		if len(b) == cap(b) {
+			b = slices.Grow(b, 1)
+		}
+		n, err := f.Read(b[len(b):cap(b)])
+		b = b[:len(b)+n]
+		if err == io.EOF {
+			return b, nil
+		}
+		if err != nil {
+			return b, err
+		}
+	}
+}
+
+// detachError returns a copy of e that doesn't refer to e.Src, which is
+// about to be released, except for the character at the error index.
+func detachError(e Error[[]byte]) Error[[]byte] {
+	src := e.Src[min(e.Index, len(e.Src)):]
+	src = src[:min(utf8.UTFMax, len(src))]
+	return Error[[]byte]{
+		Src:    append([]byte(nil), src...),
+		Index:  e.Index,
+		Code:   e.Code,
+		offset: e.Index,
+	}
+}
@@ -0,0 +1,51 @@
+//go:build linux
+
+package jscan_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScanFileFIFO tests the fallback for files that can't be mapped.
+func TestScanFileFIFO(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fifo")
+	require.NoError(t, syscall.Mkfifo(path, 0o600))
+	write := func(contents string) {
+		go func() {
+			f, err := os.OpenFile(path, os.O_WRONLY, 0)
+			if err != nil {
+				panic(err)
+			}
+			defer f.Close()
+			_, _ = f.WriteString(contents)
+		}()
+	}
+
+	write(`[1,"x"]`)
+	var values []string
+	err := jscan.ScanFile(path, func(i *jscan.Iterator[[]byte]) (err bool) {
+		values = append(values, string(i.Value()))
+		return false
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "1", `"x"`}, values)
+
+	write(`[1,"x"`)
+	err = jscan.ValidateFile(path)
+	require.Equal(t, "validate "+path+": error at index 6: unexpected EOF", err.Error())
+	var e jscan.Error[[]byte]
+	require.True(t, errors.As(err, &e))
+	require.Equal(t, jscan.ErrorCodeUnexpectedEOF, e.Code)
+
+	// The buffer grows to fit inputs of unknown size.
+	write(`[` + strings.Repeat(`{"a":[1,"x"]},`, 64*1024) + `0]`)
+	require.NoError(t, jscan.ValidateFile(path))
+}
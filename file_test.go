@@ -0,0 +1,84 @@
+package jscan_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.json")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+func TestScanFile(t *testing.T) {
+	path := writeTestFile(t, `{"a":[1,"x"]}`)
+	var values []string
+	err := jscan.ScanFile(path, func(i *jscan.Iterator[[]byte]) (err bool) {
+		values = append(values, string(i.Value()))
+		return false
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "", "1", `"x"`}, values)
+	require.NoError(t, jscan.ValidateFile(path))
+}
+
+func TestScanFileErr(t *testing.T) {
+	for _, td := range []struct {
+		name   string
+		input  string
+		expect string
+		code   jscan.ErrorCode
+		index  int
+	}{
+		{
+			name:   "syntax",
+			input:  `{"a":[1,"x"}`,
+			expect: `error at index 11 ('}'): unexpected token`,
+			code:   jscan.ErrorCodeUnexpectedToken,
+			index:  11,
+		},
+		{
+			name:   "eof",
+			input:  `{"a":`,
+			expect: `error at index 5: unexpected EOF`,
+			code:   jscan.ErrorCodeUnexpectedEOF,
+			index:  5,
+		},
+		{
+			name:   "empty",
+			input:  ``,
+			expect: `error at index 0: unexpected EOF`,
+			code:   jscan.ErrorCodeUnexpectedEOF,
+			index:  0,
+		},
+	} {
+		t.Run(td.name, func(t *testing.T) {
+			path := writeTestFile(t, td.input)
+			check := func(t *testing.T, op string, err error) {
+				t.Helper()
+				require.Equal(t, op+" "+path+": "+td.expect, err.Error())
+				var e jscan.Error[[]byte]
+				require.True(t, errors.As(err, &e))
+				require.Equal(t, td.code, e.Code)
+				require.Equal(t, td.index, e.Index)
+			}
+			check(t, "scan", jscan.ScanFile(path,
+				func(*jscan.Iterator[[]byte]) bool { return false }))
+			check(t, "validate", jscan.ValidateFile(path))
+		})
+	}
+}
+
+func TestScanFileNotExist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.json")
+	err := jscan.ScanFile(path, func(*jscan.Iterator[[]byte]) bool { return false })
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.ErrorIs(t, jscan.ValidateFile(path), os.ErrNotExist)
+}
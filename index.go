@@ -0,0 +1,234 @@
+package jscan
+
+import (
+	"math"
+	"strings"
+
+	"github.com/romshark/jscan/v2/internal/unescape"
+)
+
+// Index is a structural index of a JSON document built by BuildIndex
+// providing random access to all of its values without rescanning.
+// Values are identified by their node number, which is the order of
+// their appearance in the source, hence the root value is always node 0
+// and the members and elements of an object or array follow it directly.
+//
+// An Index is immutable and safe for concurrent use.
+// An index takes about 29 bytes of memory per node and
+// is limited to sources of up to 4 GiB.
+type Index[S ~string | ~[]byte] struct {
+	src S
+
+	typ        []ValueType
+	start, end []uint32
+	// key is the start index of the key of a member
+	// or noKey if the node isn't a member.
+	key []uint32
+	// parent is 0 for the root.
+	parent []uint32
+	// children holds the members and elements of all objects and arrays
+	// where those of node n are children[child[n]:child[n+1]].
+	child, children []uint32
+	// next is the next sibling of each node or 0 if there is none.
+	next []uint32
+}
+
+// noKey is the key index of nodes that aren't members of an object.
+const noKey uint32 = math.MaxUint32
+
+// BuildIndex scans s once and returns its structural index.
+// Returns an error if s is invalid JSON
+// or ErrorCodeTooLarge if s is larger than 4 GiB.
+func BuildIndex[S ~string | ~[]byte](s S) (*Index[S], Error[S]) {
+	if uint64(len(s)) > math.MaxUint32 {
+		return nil, Error[S]{Src: s, Index: len(s), Code: ErrorCodeTooLarge}
+	}
+	x := &Index[S]{src: s}
+	var parents []uint32
+	i := newIterator[S]()
+	i.endEvents = true
+	err := (&Parser[S]{i: i}).Scan(s, func(i *Iterator[S]) (err bool) {
+		if i.end {
+			x.end[parents[len(parents)-1]] = uint32(i.valueIndexEnd)
+			parents = parents[:len(parents)-1]
+			return false
+		}
+		n, parent := uint32(len(x.typ)), uint32(0)
+		if len(parents) > 0 {
+			parent = parents[len(parents)-1]
+			x.child[parent]++
+		}
+		x.typ = append(x.typ, i.valueType)
+		x.start = append(x.start, uint32(i.valueIndex))
+		x.end = append(x.end, uint32(i.valueIndexEnd))
+		if i.keyIndex == -1 {
+			x.key = append(x.key, noKey)
+		} else {
+			x.key = append(x.key, uint32(i.keyIndex))
+		}
+		x.parent = append(x.parent, parent)
+		x.child = append(x.child, 0)
+		if i.valueType == ValueTypeObject || i.valueType == ValueTypeArray {
+			parents = append(parents, n)
+		}
+		return false
+	})
+	if err.IsErr() {
+		return nil, err
+	}
+	x.buildChildren()
+	return x, Error[S]{}
+}
+
+// buildChildren builds x.children and x.next from x.parent given that
+// x.child holds the number of children of every node.
+func (x *Index[S]) buildChildren() {
+	// Turn the numbers of children into the ends of the lists of children
+	// and fill the lists from the back such that x.child[n] ends up
+	// being the start of the list of node n.
+	sum := uint32(0)
+	for n := range x.child {
+		sum += x.child[n]
+		x.child[n] = sum
+	}
+	x.child = append(x.child, sum)
+	x.children = make([]uint32, sum)
+	x.next = make([]uint32, len(x.parent))
+	for n := len(x.parent) - 1; n > 0; n-- {
+		p := x.parent[n]
+		x.child[p]--
+		x.children[x.child[p]] = uint32(n)
+	}
+	for n := 0; n < len(x.parent); n++ {
+		siblings := x.children[x.child[n]:x.child[n+1]]
+		for j := 1; j < len(siblings); j++ {
+			x.next[siblings[j-1]] = siblings[j]
+		}
+	}
+}
+
+// Src returns the indexed source.
+func (x *Index[S]) Src() S { return x.src }
+
+// Nodes returns the number of values in the document.
+func (x *Index[S]) Nodes() int { return len(x.typ) }
+
+// Type returns the type of node n.
+func (x *Index[S]) Type(n int) ValueType { return x.typ[n] }
+
+// ValueIndex returns the start index of node n in the source.
+func (x *Index[S]) ValueIndex(n int) int { return int(x.start[n]) }
+
+// ValueIndexEnd returns the end index of node n in the source.
+func (x *Index[S]) ValueIndexEnd(n int) int { return int(x.end[n]) }
+
+// Value returns node n as it appears in the source.
+func (x *Index[S]) Value(n int) S { return x.src[x.start[n]:x.end[n]] }
+
+// Key returns the key of node n including the quotation marks
+// or "" if n isn't a member of an object.
+func (x *Index[S]) Key(n int) (key S) {
+	if x.key[n] == noKey {
+		return
+	}
+	return x.src[x.key[n]:x.keyEnd(n)]
+}
+
+// keyEnd returns the end index of the key of member n, which is
+// followed by a colon surrounded by optional whitespace.
+func (x *Index[S]) keyEnd(n int) int {
+	j := int(x.start[n]) - 1
+	for x.src[j] != ':' {
+		j--
+	}
+	for j--; lutSX[x.src[j]] == 1; j-- {
+		// Skip whitespace between the key and the colon.
+	}
+	return j + 1
+}
+
+// Len returns the number of members or elements of node n
+// or 0 if it's neither an object nor an array.
+func (x *Index[S]) Len(n int) int { return int(x.child[n+1] - x.child[n]) }
+
+// Parent returns the object or array containing node n
+// or -1 if n is the root.
+func (x *Index[S]) Parent(n int) int {
+	if n == 0 {
+		return -1
+	}
+	return int(x.parent[n])
+}
+
+// FirstChild returns the first member or element of node n
+// or -1 if n is neither an object nor an array or is empty.
+func (x *Index[S]) FirstChild(n int) int {
+	if x.Len(n) == 0 {
+		return -1
+	}
+	return int(x.children[x.child[n]])
+}
+
+// NextSibling returns the member or element following node n
+// or -1 if n is the last one or the root.
+func (x *Index[S]) NextSibling(n int) int {
+	if x.next[n] == 0 {
+		// The root is never a sibling.
+		return -1
+	}
+	return int(x.next[n])
+}
+
+// Field returns the member of object node n with the unescaped key name
+// or -1 if there is no such member or n isn't an object.
+// If the object has multiple members with the key, the first one is returned.
+func (x *Index[S]) Field(n int, name string) int {
+	if x.typ[n] != ValueTypeObject {
+		return -1
+	}
+	for _, c := range x.children[x.child[n]:x.child[n+1]] {
+		if unescape.Equal(x.src[x.key[c]+1:x.keyEnd(int(c))-1], name) {
+			return int(c)
+		}
+	}
+	return -1
+}
+
+// Elem returns the element at the given index of array node n
+// or -1 if index is out of range or n isn't an array.
+func (x *Index[S]) Elem(n int, index int) int {
+	if x.typ[n] != ValueTypeArray || index < 0 || index >= x.Len(n) {
+		return -1
+	}
+	return int(x.children[int(x.child[n])+index])
+}
+
+// Lookup returns the node referenced by the JSON pointer (RFC 6901).
+// Returns -1 and false if there is no such node or the pointer is invalid.
+func (x *Index[S]) Lookup(pointer string) (n int, ok bool) {
+	if len(x.typ) == 0 || (pointer != "" && pointer[0] != '/') {
+		return -1, false
+	}
+	for s := pointer; s != "" && n != -1; {
+		s = s[1:]
+		token := s
+		if j := strings.IndexByte(s, '/'); j != -1 {
+			token, s = s[:j], s[j:]
+		} else {
+			s = ""
+		}
+		switch x.typ[n] {
+		case ValueTypeObject:
+			name, err := unescapePatternToken(token)
+			if err != nil {
+				return -1, false
+			}
+			n = x.Field(n, name)
+		case ValueTypeArray:
+			n = x.Elem(n, parseArrayIndex(token))
+		default:
+			return -1, false
+		}
+	}
+	return n, n != -1
+}
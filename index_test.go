@@ -0,0 +1,178 @@
+package jscan_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildIndex(t *testing.T) {
+	for _, input := range []string{
+		`42`,
+		`{}`,
+		` [ ] `,
+		`{"a":[1,{"b":null},[]],"c/d":{"e~f":"g","":true},"h":-1.5}`,
+		`[[[0]],{"x":{"y":[0,1,{"z":"w"}]}},"s",false]`,
+		"{ \"a\" :\t[ 1 , 2 ] ,\n\"b\"\n:\n{ } }",
+	} {
+		t.Run(input, func(t *testing.T) {
+			testBuildIndex(t, input)
+			testBuildIndex(t, []byte(input))
+		})
+	}
+}
+
+func testBuildIndex[S ~string | ~[]byte](t *testing.T, input S) {
+	x, err := jscan.BuildIndex(input)
+	require.False(t, err.IsErr(), "unexpected error: %v", err)
+	require.Equal(t, input, x.Src())
+
+	n := 0
+	p := jscan.NewParserWithOptions[S](64, jscan.ParserOptions{EndEvents: true})
+	err = p.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+		if i.IsEnd() {
+			// Find the node of the container that just ended.
+			c, ok := x.Lookup(string(i.Pointer()))
+			require.True(t, ok)
+			require.Equal(t, i.Len(), x.Len(c))
+			require.Equal(t, i.ValueIndexEnd(), x.ValueIndexEnd(c))
+			require.Equal(t, string(i.Value()), string(x.Value(c)))
+			return false
+		}
+		require.Equal(t, i.ValueType(), x.Type(n))
+		require.Equal(t, i.ValueIndex(), x.ValueIndex(n))
+		require.Equal(t, string(i.Key()), string(x.Key(n)))
+
+		l, ok := x.Lookup(string(i.Pointer()))
+		require.True(t, ok, "pointer: %q", i.Pointer())
+		require.Equal(t, n, l)
+
+		if i.Level() == 0 {
+			require.Equal(t, -1, x.Parent(n))
+		} else {
+			parent := x.Parent(n)
+			require.Equal(t, i.Level()-1, level(x, parent))
+			if ai := i.ArrayIndex(); ai != -1 {
+				require.Equal(t, n, x.Elem(parent, ai))
+			}
+		}
+		n++
+		return false
+	})
+	require.False(t, err.IsErr())
+	require.Equal(t, n, x.Nodes())
+
+	// Iterate children of every node.
+	for n := 0; n < x.Nodes(); n++ {
+		c, l := x.FirstChild(n), 0
+		for ; c != -1; c = x.NextSibling(c) {
+			require.Equal(t, n, x.Parent(c))
+			l++
+		}
+		require.Equal(t, x.Len(n), l)
+	}
+}
+
+func level[S ~string | ~[]byte](x *jscan.Index[S], n int) (l int) {
+	for n = x.Parent(n); n != -1; n = x.Parent(n) {
+		l++
+	}
+	return l
+}
+
+func TestIndexLookup(t *testing.T) {
+	x, err := jscan.BuildIndex(
+		`{"a":[1,{"b":null},[]],"c\/d":{"e~f":"g","":true},"h":-1.5,"h":2}`,
+	)
+	require.False(t, err.IsErr())
+
+	for _, td := range []struct {
+		pointer string
+		expect  string
+	}{
+		{``, `{"a":[1,{"b":null},[]],"c\/d":{"e~f":"g","":true},"h":-1.5,"h":2}`},
+		{`/a`, `[1,{"b":null},[]]`},
+		{`/a/0`, `1`},
+		{`/a/1/b`, `null`},
+		{`/a/2`, `[]`},
+		{`/c~1d`, `{"e~f":"g","":true}`},
+		{`/c~1d/e~0f`, `"g"`},
+		{`/c~1d/`, `true`},
+		{`/h`, `-1.5`},
+	} {
+		n, ok := x.Lookup(td.pointer)
+		require.True(t, ok, "pointer: %q", td.pointer)
+		require.Equal(t, td.expect, x.Value(n), "pointer: %q", td.pointer)
+	}
+
+	for _, pointer := range []string{
+		`a`, `/x`, `/a/3`, `/a/-1`, `/a/01`, `/a/b`, `/a/0/x`,
+		`/c~2d`, `/c~`, `/h/0`,
+	} {
+		n, ok := x.Lookup(pointer)
+		require.False(t, ok, "pointer: %q", pointer)
+		require.Equal(t, -1, n)
+	}
+
+	require.Equal(t, -1, x.Field(0, "x"))
+	require.Equal(t, -1, x.Elem(0, 0))
+	require.Equal(t, -1, x.FirstChild(1+x.Len(1)))
+}
+
+func TestIndexElem(t *testing.T) {
+	var b strings.Builder
+	b.WriteByte('[')
+	for j := 0; j < 1000; j++ {
+		if j > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "[%d]", j)
+	}
+	b.WriteByte(']')
+	x, err := jscan.BuildIndex(b.String())
+	require.False(t, err.IsErr())
+	require.Equal(t, 1000, x.Len(0))
+	for j := 0; j < 1000; j++ {
+		n := x.Elem(0, j)
+		require.Equal(t, fmt.Sprintf("[%d]", j), x.Value(n))
+		require.Equal(t, fmt.Sprint(j), x.Value(x.Elem(n, 0)))
+		if j < 999 {
+			require.Equal(t, n+2, x.NextSibling(n))
+		} else {
+			require.Equal(t, -1, x.NextSibling(n))
+		}
+	}
+	require.Equal(t, -1, x.Elem(0, 1000))
+}
+
+func TestBuildIndexErr(t *testing.T) {
+	x, err := jscan.BuildIndex(`{"a":[1,2}`)
+	require.Nil(t, x)
+	require.Equal(t, jscan.Error[string]{
+		Src: `{"a":[1,2}`, Index: 9, Code: jscan.ErrorCodeUnexpectedToken,
+	}, err)
+}
+
+func TestIndexConcurrentLookup(t *testing.T) {
+	x, err := jscan.BuildIndex([]byte(`{"a":{"b":[0,1,2,{"c":"d"}]}}`))
+	require.False(t, err.IsErr())
+	var wg sync.WaitGroup
+	for j := 0; j < 8; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < 1000; k++ {
+				n, ok := x.Lookup("/a/b/3/c")
+				if !ok || string(x.Value(n)) != `"d"` {
+					t.Error("unexpected lookup result")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,162 @@
+package unescape
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// lutEscape maps the second character of a short escape sequence
+// to the character it represents, all other characters are mapped to 0.
+var lutEscape = [256]byte{
+	'"':  '"',
+	'\\': '\\',
+	'/':  '/',
+	'b':  '\b',
+	'f':  '\f',
+	'n':  '\n',
+	'r':  '\r',
+	't':  '\t',
+}
+
+// Rune decodes the escape sequence at the beginning of s and returns
+// the rune it represents and the number of bytes it occupies in s.
+// A UTF-16 surrogate pair spanning two consecutive \uXXXX sequences is
+// decoded into a single rune. Lone surrogates are decoded to utf8.RuneError.
+// s is expected to be a valid JSON escape sequence starting with '\'.
+func Rune[S ~string | ~[]byte](s S) (r rune, size int) {
+	if s[1] != 'u' {
+		return rune(lutEscape[s[1]]), 2
+	}
+	r = hex4(s[2:6])
+	if r < 0xD800 || r > 0xDFFF {
+		return r, 6
+	}
+	if r > 0xDBFF {
+		// Lone low surrogate
+		return utf8.RuneError, 6
+	}
+	if len(s) < 12 || s[6] != '\\' || s[7] != 'u' {
+		// High surrogate not followed by an escape sequence
+		return utf8.RuneError, 6
+	}
+	lo := hex4(s[8:12])
+	if lo < 0xDC00 || lo > 0xDFFF {
+		// High surrogate not followed by a low surrogate
+		return utf8.RuneError, 6
+	}
+	return 0x10000 + (r-0xD800)<<10 + (lo - 0xDC00), 12
+}
+
+// Append appends the unescaped contents of the JSON string s to dst.
+// s is expected to be the contents of a valid JSON string
+// without the surrounding quotation marks.
+func Append[S ~string | ~[]byte](dst []byte, s S) []byte {
+	for len(s) > 0 {
+		i := 0
+		for i < len(s) && s[i] != '\\' {
+			i++
+		}
+		dst = append(dst, s[:i]...)
+		if s = s[i:]; len(s) < 1 {
+			break
+		}
+		r, n := Rune(s)
+		dst = utf8.AppendRune(dst, r)
+		s = s[n:]
+	}
+	return dst
+}
+
+// hex4 decodes the four hexadecimal digits in s.
+func hex4[S ~string | ~[]byte](s S) (r rune) {
+	for i := 0; i < 4; i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'a' && c <= 'f':
+			c = c - 'a' + 10
+		default:
+			c = c - 'A' + 10
+		}
+		r = r<<4 | rune(c)
+	}
+	return r
+}
+
+// Equal returns true if the unescaped contents of the JSON string s
+// equal t, otherwise returns false. Equal doesn't allocate.
+// s is expected to be the contents of a valid JSON string
+// without the surrounding quotation marks.
+func Equal[S ~string | ~[]byte](s S, t string) bool {
+	for len(s) > 0 {
+		if s[0] != '\\' {
+			if len(t) < 1 || s[0] != t[0] {
+				return false
+			}
+			s, t = s[1:], t[1:]
+			continue
+		}
+		r, n := Rune(s)
+		s = s[n:]
+		var b [utf8.UTFMax]byte
+		m := utf8.EncodeRune(b[:], r)
+		if len(t) < m || t[:m] != string(b[:m]) {
+			return false
+		}
+		t = t[m:]
+	}
+	return len(t) == 0
+}
+
+// EqualFold is similar to Equal but compares under simple Unicode
+// case-folding just like strings.EqualFold. EqualFold doesn't allocate.
+func EqualFold[S ~string | ~[]byte](s S, t string) bool {
+	for len(s) > 0 && len(t) > 0 {
+		var sr, tr rune
+		if s[0] < utf8.RuneSelf && t[0] < utf8.RuneSelf && s[0] != '\\' {
+			// ASCII fast path
+			sr, tr = rune(s[0]), rune(t[0])
+			s, t = s[1:], t[1:]
+		} else {
+			var n int
+			if s[0] == '\\' {
+				sr, n = Rune(s)
+			} else {
+				sr, n = decodeRune(s)
+			}
+			s = s[n:]
+			tr, n = utf8.DecodeRuneInString(t)
+			t = t[n:]
+		}
+		if sr == tr {
+			continue
+		}
+		if tr < sr {
+			sr, tr = tr, sr
+		}
+		if tr < utf8.RuneSelf {
+			if 'A' <= sr && sr <= 'Z' && tr == sr+'a'-'A' {
+				continue
+			}
+			return false
+		}
+		// General case, SimpleFold(x) returns the next equivalent rune > x
+		// or wraps around to smaller values.
+		r := unicode.SimpleFold(sr)
+		for r != sr && r < tr {
+			r = unicode.SimpleFold(r)
+		}
+		if r != tr {
+			return false
+		}
+	}
+	return len(s) == 0 && len(t) == 0
+}
+
+// decodeRune decodes the UTF-8 encoded rune at the beginning of s.
+func decodeRune[S ~string | ~[]byte](s S) (r rune, size int) {
+	var b [utf8.UTFMax]byte
+	n := copy(b[:], s)
+	return utf8.DecodeRune(b[:n])
+}
@@ -0,0 +1,139 @@
+package unescape_test
+
+import (
+	"encoding/json"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/romshark/jscan/v2/internal/unescape"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRune(t *testing.T) {
+	for _, tt := range []struct {
+		input  string
+		expect rune
+		size   int
+	}{
+		{`\"`, '"', 2},
+		{`\\`, '\\', 2},
+		{`\/`, '/', 2},
+		{`\b`, '\b', 2},
+		{`\f`, '\f', 2},
+		{`\n`, '\n', 2},
+		{`\r`, '\r', 2},
+		{`\t`, '\t', 2},
+		{`\u0041`, 'A', 6},
+		{`\u00e4`, 'ä', 6},
+		{`\u00E4`, 'ä', 6},
+		{`\uffff`, '\uffff', 6},
+		{`\ud83d\ude00`, '😀', 12},
+		{`\ud83d\ude00xyz`, '😀', 12},
+		{`\ud83d`, utf8.RuneError, 6},
+		{`\ud83dx`, utf8.RuneError, 6},
+		{`\ud83d\n`, utf8.RuneError, 6},
+		{`\ud83d\u0041`, utf8.RuneError, 6},
+		{`\ude00`, utf8.RuneError, 6},
+	} {
+		t.Run(tt.input, func(t *testing.T) {
+			r, size := unescape.Rune(tt.input)
+			require.Equal(t, tt.expect, r)
+			require.Equal(t, tt.size, size)
+			r, size = unescape.Rune([]byte(tt.input))
+			require.Equal(t, tt.expect, r)
+			require.Equal(t, tt.size, size)
+		})
+	}
+}
+
+func TestAppend(t *testing.T) {
+	for _, input := range []string{
+		``,
+		`abc`,
+		`\"quoted\"`,
+		`a\\b\/c`,
+		`\b\f\n\r\t`,
+		`\u00e4ä \ud83d\ude00😀`,
+		`\ud83d lone`,
+		`lone \ude00`,
+		`\u0000`,
+		`long text without any escape sequences at all`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			var expect string
+			require.NoError(t, json.Unmarshal([]byte(`"`+input+`"`), &expect))
+
+			prefix := []byte("prefix:")
+			require.Equal(t, "prefix:"+expect,
+				string(unescape.Append(prefix, input)))
+			require.Equal(t, "prefix:"+expect,
+				string(unescape.Append(prefix, []byte(input))))
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	for _, tt := range []struct {
+		input  string
+		t      string
+		expect bool
+	}{
+		{``, ``, true},
+		{``, `a`, false},
+		{`a`, ``, false},
+		{`abc`, `abc`, true},
+		{`abc`, `abd`, false},
+		{`abc`, `abcd`, false},
+		{`\"quoted \"`, `"quoted "`, true},
+		{`\nx`, "\nx", true},
+		{`\nx`, "\ny", false},
+		{`\u00e4`, `ä`, true},
+		{`\u00e4`, `a`, false},
+		{`\u00e4x`, `äx`, true},
+		{`\ud83d\ude00`, `😀`, true},
+		{`\ud83d\ude00`, `😁`, false},
+		{`\ud83d`, "\uFFFD", true},
+	} {
+		t.Run(tt.input, func(t *testing.T) {
+			require.Equal(t, tt.expect, unescape.Equal(tt.input, tt.t))
+			require.Equal(t, tt.expect, unescape.Equal([]byte(tt.input), tt.t))
+		})
+	}
+}
+
+func TestEqualFold(t *testing.T) {
+	for _, tt := range []struct {
+		input  string
+		t      string
+		expect bool
+	}{
+		{``, ``, true},
+		{``, `a`, false},
+		{`a`, ``, false},
+		{`abc`, `ABC`, true},
+		{`aBc`, `AbC`, true},
+		{`abc`, `abd`, false},
+		{`abc`, `abcd`, false},
+		{`@`, "`", false},
+		{`\u0041b`, `ab`, true},
+		{`\u0041b`, `Ab`, true},
+		{`\u0041b`, `ac`, false},
+		{`\nX`, `\nx`, false},
+		{`\nX`, "\nx", true},
+		{`\u00e4`, `Ä`, true},
+		{`ä`, `Ä`, true},
+		{`ä`, `\u00c4`, false},
+		{`k`, "\u212a", true},
+		{`\u212a`, `K`, true},
+		{`\ud83d\ude00`, `😀`, true},
+		{`\ud83d\ude00`, `😁`, false},
+		{`σ`, `Σ`, true},
+		{`ς`, `Σ`, true},
+	} {
+		t.Run(tt.input, func(t *testing.T) {
+			require.Equal(t, tt.expect, unescape.EqualFold(tt.input, tt.t))
+			require.Equal(t, tt.expect, unescape.EqualFold([]byte(tt.input), tt.t))
+		})
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"sync"
 	"unicode/utf8"
 
+	"github.com/romshark/jscan/v2/internal/jsonnum"
 	"github.com/romshark/jscan/v2/internal/keyescape"
+	"github.com/romshark/jscan/v2/internal/unescape"
 )
 
 // Default stack sizes
@@ -16,7 +18,10 @@ const (
 )
 
 func newIterator[S ~string | ~[]byte]() *Iterator[S] {
-	return &Iterator[S]{stack: make([]stackNode, 0, DefaultStackSizeIterator)}
+	return &Iterator[S]{
+		stack:     make([]stackNode, 0, DefaultStackSizeIterator),
+		skipStack: make([]stackNodeType, 0, DefaultStackSizeValidator),
+	}
 }
 
 func newValidator[S ~string | ~[]byte]() *Validator[S] {
@@ -28,6 +33,8 @@ var (
 	iteratorPoolBytes   = sync.Pool{New: func() any { return newIterator[[]byte]() }}
 	validatorPoolString = sync.Pool{New: func() any { return newValidator[string]() }}
 	validatorPoolBytes  = sync.Pool{New: func() any { return newValidator[[]byte]() }}
+	pushParserPool      = sync.Pool{New: func() any { return NewPushParser(nil) }}
+	filePool            = sync.Pool{New: func() any { return new([]byte) }}
 )
 
 type stackNodeType int8
@@ -39,9 +46,27 @@ const (
 )
 
 type stackNode struct {
-	ArrLen                int
+	// Len is the number of elements or members encountered so far.
+	// Members are only counted when end events are enabled.
+	Len                   int
 	KeyIndex, KeyIndexEnd int
-	Type                  stackNodeType
+	// Index is the start index of the object or array in the source.
+	Index int
+	Type  stackNodeType
+}
+
+// push pushes the object or array of type t starting at i.valueIndex
+// onto the stack. The fields are assigned individually since
+// initializing a stackNode by a composite literal is considerably slower.
+func (i *Iterator[S]) push(t stackNodeType, keyIndex, keyIndexEnd, l int) {
+	if len(i.stack) == cap(i.stack) {
+		i.stack = append(i.stack, stackNode{})
+	} else {
+		i.stack = i.stack[:len(i.stack)+1]
+	}
+	n := &i.stack[len(i.stack)-1]
+	n.Type, n.Len, n.Index = t, l, i.valueIndex
+	n.KeyIndex, n.KeyIndexEnd = keyIndex, keyIndexEnd
 }
 
 // Iterator provides access to the recently encountered value.
@@ -55,6 +80,73 @@ type Iterator[S ~string | ~[]byte] struct {
 	valueIndexEnd         int
 	keyIndex, keyIndexEnd int
 	arrayIndex            int
+
+	// The fields above are accessed for every value and
+	// are kept together at the start of the struct.
+
+	skipStack []stackNodeType
+	path      []byte
+
+	// skip is set when the children of the current object or array
+	// shall be skipped without invoking the callback.
+	skip bool
+
+	// valueEscaped is set when the current string value
+	// contains escape sequences.
+	valueEscaped bool
+
+	// numberKind is the return code of jsonnum.ReadNumber
+	// for the current number value.
+	numberKind jsonnum.ReturnCode
+
+	// endEvents enables invoking the callback at the end of objects and arrays.
+	endEvents bool
+	// end is true during the invocation of an end event.
+	end bool
+	// len is the number of elements or members of the object or array
+	// during an end event, otherwise -1.
+	len int
+
+	// trackPointer enables keeping pointer up to date during traversal.
+	trackPointer bool
+	// pointerEnds holds the length of the pointer of each object and array
+	// on the stack when trackPointer is enabled.
+	pointerEnds []int
+
+	// resumeState and resumeOffset define where scan resumes
+	// when resuming from a checkpoint.
+	resumeState  checkpointState
+	resumeOffset int
+
+	// pull is set by Reader, which stops scan at every value.
+	// When fn stops scan no error is returned, see Reader.Next.
+	pull bool
+
+	// progress is the progress hook used by (*Parser).ScanContext.
+	progress func(consumed int)
+
+	// check is the context checker of ScanContext, which is also
+	// checked while skipped objects and arrays are validated.
+	check *contextChecker
+
+	// offset is the index of src[0] in the stream when scanning a stream,
+	// in which case src is only the buffered part of the stream
+	// and the indexes of the stack are relative to the stream.
+	offset int
+
+	// stream is set when scanning a stream.
+	stream bool
+
+	// keys holds copies of the keys of the objects and arrays on the stack
+	// that are no longer buffered when scanning a stream, see stackKey.
+	keys []savedKey
+
+	// scratch is a buffer used for decoding escaped string values.
+	scratch []byte
+
+	// matchCache and routeCache are the caches used for matching
+	// Patterns by Match and Router respectively.
+	matchCache, routeCache patternCache
 }
 
 // Level returns the depth level of the current value.
@@ -71,20 +163,48 @@ func (i *Iterator[S]) ArrayIndex() int { return i.arrayIndex }
 func (i *Iterator[S]) ValueType() ValueType { return i.valueType }
 
 // ValueIndex returns the start index of the value in the source.
-func (i *Iterator[S]) ValueIndex() int { return i.valueIndex }
+func (i *Iterator[S]) ValueIndex() int { return i.offset + i.valueIndex }
 
 // ValueIndexEnd returns the end index of the value in the source if any.
 // Object and array values have a -1 end index because their end is unknown
-// during traversal.
-func (i *Iterator[S]) ValueIndexEnd() int { return i.valueIndexEnd }
+// during traversal, except during end events (see IsEnd).
+func (i *Iterator[S]) ValueIndexEnd() int {
+	if i.valueIndexEnd == -1 {
+		return -1
+	}
+	return i.offset + i.valueIndexEnd
+}
+
+// IsEnd returns true if the end of an object or array was reached.
+// End events are only reported by parsers created with
+// ParserOptions.EndEvents enabled.
+//
+// During an end event ValueType, ValueIndex, ValueIndexEnd, Key, ArrayIndex,
+// Level and Pointer all refer to the object or array that just ended,
+// Value returns the entire object or array and Len returns the number of its
+// members or elements, or -1 if its children were skipped
+// using ActionSkipChildren or CaptureRaw.
+func (i *Iterator[S]) IsEnd() bool { return i.end }
+
+// Len returns the number of members or elements of the object or array
+// during an end event (see IsEnd).
+// Returns -1 for all other events and for objects and arrays
+// the children of which were skipped using ActionSkipChildren.
+func (i *Iterator[S]) Len() int { return i.len }
 
 // KeyIndex returns either the start index of the member key string in the source
 // or -1 when the value isn't a member of an object and hence doesn't have a key.
-func (i *Iterator[S]) KeyIndex() int { return i.keyIndex }
+func (i *Iterator[S]) KeyIndex() int {
+	// Branch-free version of adding offset unless the index is -1.
+	return i.keyIndex + i.offset&^(i.keyIndex>>63)
+}
 
 // KeyIndexEnd returns either the end index of the member key string in the source
 // or -1 when the value isn't a member of an object and hence doesn't have a key.
-func (i *Iterator[S]) KeyIndexEnd() int { return i.keyIndexEnd }
+func (i *Iterator[S]) KeyIndexEnd() int {
+	// Branch-free version of adding offset unless the index is -1.
+	return i.keyIndexEnd + i.offset&^(i.keyIndexEnd>>63)
+}
 
 // Key returns either the object member key or "" when the value
 // isn't a member of an object and hence doesn't have a key.
@@ -95,6 +215,90 @@ func (i *Iterator[S]) Key() (key S) {
 	return i.src[i.keyIndex:i.keyIndexEnd]
 }
 
+// KeyEquals returns true if the value is a member of an object and
+// its unescaped key equals s, otherwise returns false.
+// KeyEquals doesn't allocate.
+func (i *Iterator[S]) KeyEquals(s string) bool {
+	if i.keyIndex == -1 {
+		return false
+	}
+	key := i.src[i.keyIndex+1 : i.keyIndexEnd-1]
+	switch {
+	case len(key) == len(s):
+		// Escape sequences are always longer than the characters they
+		// represent, hence key can only be equal if it contains none.
+		for j := 0; j < len(s); j++ {
+			if key[j] != s[j] || key[j] == '\\' {
+				return false
+			}
+		}
+		return true
+	case len(key) < len(s):
+		return false
+	}
+	return unescape.Equal(key, s)
+}
+
+// KeyEqualFold is similar to KeyEquals but compares under simple
+// Unicode case-folding just like strings.EqualFold.
+// KeyEqualFold doesn't allocate.
+func (i *Iterator[S]) KeyEqualFold(s string) bool {
+	if i.keyIndex == -1 {
+		return false
+	}
+	return unescape.EqualFold(i.src[i.keyIndex+1:i.keyIndexEnd-1], s)
+}
+
+// NumberKind returns the notation of the number value
+// or 0 if the value isn't a number.
+// Note that a number of kind NumberKindFloat may still be integral,
+// such as 1.0 or 1e3.
+func (i *Iterator[S]) NumberKind() NumberKind {
+	if i.valueType != ValueTypeNumber {
+		return 0
+	}
+	if i.numberKind == jsonnum.ReturnCodeInteger {
+		return NumberKindInteger
+	}
+	return NumberKindFloat
+}
+
+// AppendUnescapedKey appends the decoded UTF-8 member key without
+// the surrounding quotation marks to dst and returns the extended buffer.
+// dst is returned unchanged if the value isn't a member of an object.
+// Lone UTF-16 surrogates in \uXXXX escape sequences are decoded to
+// utf8.RuneError.
+func (i *Iterator[S]) AppendUnescapedKey(dst []byte) []byte {
+	if i.keyIndex == -1 {
+		return dst
+	}
+	return unescape.Append(dst, i.src[i.keyIndex+1:i.keyIndexEnd-1])
+}
+
+// AppendUnescapedValue appends the decoded UTF-8 string value without
+// the surrounding quotation marks to dst and returns the extended buffer.
+// Values of any other type are appended as they appear in the source.
+// Lone UTF-16 surrogates in \uXXXX escape sequences are decoded to
+// utf8.RuneError.
+func (i *Iterator[S]) AppendUnescapedValue(dst []byte) []byte {
+	if i.valueType != ValueTypeString {
+		return append(dst, i.Value()...)
+	}
+	v := i.src[i.valueIndex+1 : i.valueIndexEnd-1]
+	if !i.valueEscaped {
+		return append(dst, v...)
+	}
+	return unescape.Append(dst, v)
+}
+
+// ValueHasEscapes returns true if the value is a string containing
+// escape sequences, otherwise returns false.
+// Strings without escape sequences can be used as they appear in the source
+// (excluding the surrounding quotation marks) without decoding.
+func (i *Iterator[S]) ValueHasEscapes() bool {
+	return i.valueType == ValueTypeString && i.valueEscaped
+}
+
 // Value returns the value if any.
 func (i *Iterator[S]) Value() (value S) {
 	if i.valueIndexEnd == -1 {
@@ -103,6 +307,34 @@ func (i *Iterator[S]) Value() (value S) {
 	return i.src[i.valueIndex:i.valueIndexEnd]
 }
 
+// CaptureRaw returns the entire current value as it appears in the source.
+// When called on the start of an object or array CaptureRaw scans ahead to
+// its closing bracket and the member and element values are then skipped
+// without invoking the callback, as with ActionSkipChildren.
+// Returns "" if the object or array is malformed, in which case the scan
+// stops with the corresponding error after the callback returns, and when
+// scanning with ScanContext also if the context is done before it ends.
+// When scanning a stream objects and arrays aren't buffered, hence
+// CaptureRaw always returns "" for them but still skips their children.
+//
+// The returned value aliases the source. CaptureRaw is safe to call
+// repeatedly for the same value.
+func (i *Iterator[S]) CaptureRaw() (raw S) {
+	if i.valueIndexEnd == -1 && (i.valueType == ValueTypeObject ||
+		i.valueType == ValueTypeArray) {
+		i.skip = true
+		if i.stream {
+			return
+		}
+		t, err := i.validateValue()
+		if err.IsErr() {
+			return
+		}
+		i.valueIndexEnd = len(i.src) - len(t)
+	}
+	return i.Value()
+}
+
 // ScanStack calls fn for every element in the stack.
 // If keyIndex is != -1 then the element is a member value, otherwise
 // arrayIndex indicates the index of the element in the underlying array.
@@ -112,11 +344,102 @@ func (i *Iterator[S]) ScanStack(fn func(keyIndex, keyEnd, arrayIndex int)) {
 			fn(i.stack[j].KeyIndex, i.stack[j].KeyIndexEnd, -1)
 		}
 		if i.stack[j].Type == stackNodeTypeArray {
-			fn(-1, -1, i.stack[j].ArrLen-1)
+			fn(-1, -1, i.stack[j].Len-1)
 		}
 	}
 }
 
+// scanStack is similar to ScanStack but provides the keys including
+// the quotes instead of their indexes, which can't be used to slice
+// src when scanning a stream. key is "" for array elements.
+func (i *Iterator[S]) scanStack(fn func(key S, arrayIndex int)) {
+	for j := range i.stack {
+		if i.stack[j].KeyIndex > -1 {
+			fn(i.stackKey(j), -1)
+		}
+		if i.stack[j].Type == stackNodeTypeArray {
+			var key S
+			fn(key, i.stack[j].Len-1)
+		}
+	}
+}
+
+// savedKey is a copy of the key starting at index in the stream.
+type savedKey struct {
+	index int
+	b     []byte
+}
+
+// stackKey returns the key of the object or array at the given level
+// of the stack or "" if it isn't a member of an object.
+func (i *Iterator[S]) stackKey(level int) (key S) {
+	n := &i.stack[level]
+	if n.KeyIndex == -1 {
+		return
+	}
+	if n.KeyIndex < i.offset {
+		// The key is no longer buffered, see (*streamScanner).saveKeys.
+		return S(i.keys[level].b)
+	}
+	return i.src[n.KeyIndex-i.offset : n.KeyIndexEnd-i.offset]
+}
+
+// Frame is a view of an object or array the current value is nested in.
+type Frame[S ~string | ~[]byte] struct {
+	key S
+
+	// ValueType is either ValueTypeObject or ValueTypeArray.
+	ValueType ValueType
+
+	// Index is the start index of the object or array in the source.
+	Index int
+
+	// KeyIndex and KeyIndexEnd are the start and end index of the member key
+	// in the source or -1 if the object or array isn't a member of an object.
+	KeyIndex, KeyIndexEnd int
+
+	// ArrayIndex is the index of the object or array in its parent array
+	// or -1 if it isn't an element of an array.
+	ArrayIndex int
+
+	// Len is the number of members or elements encountered so far.
+	// Members of objects are only counted by stream parsers and parsers with
+	// ParserOptions.EndEvents enabled, Len of objects is 0 otherwise.
+	Len int
+}
+
+// Key returns either the member key of the object or array or ""
+// if it isn't a member of an object and hence doesn't have a key.
+func (f Frame[S]) Key() S { return f.key }
+
+// Frame returns the object or array at the given level the current value
+// is nested in. level must be in the range [0, Level()), where level 0 refers
+// to the root object or array and Level()-1 to the direct parent.
+// Frame panics if level is out of range.
+func (i *Iterator[S]) Frame(level int) Frame[S] {
+	n := i.stack[level]
+	f := Frame[S]{
+		key:         i.stackKey(level),
+		ValueType:   ValueTypeObject,
+		Index:       n.Index,
+		KeyIndex:    n.KeyIndex,
+		KeyIndexEnd: n.KeyIndexEnd,
+		ArrayIndex:  -1,
+		Len:         n.Len,
+	}
+	if n.Type == stackNodeTypeArray {
+		f.ValueType = ValueTypeArray
+	}
+	if n.KeyIndex == -1 {
+		// KeyIndexEnd isn't reset for values that aren't members.
+		f.KeyIndexEnd = -1
+	}
+	if level > 0 && i.stack[level-1].Type == stackNodeTypeArray {
+		f.ArrayIndex = i.stack[level-1].Len - 1
+	}
+	return f
+}
+
 // Pointer returns the JSON pointer in RFC-6901 format.
 func (i *Iterator[S]) Pointer() (s S) {
 	i.ViewPointer(func(p []byte) {
@@ -135,15 +458,34 @@ func (i *Iterator[S]) Pointer() (s S) {
 // ViewPointer calls fn and provides the buffer holding the
 // JSON pointer in RFC-6901 format.
 // Consider using (*Iterator[S]).Pointer() instead for safety and convenience.
+// The pointer is rebuilt on every call unless ParserOptions.TrackPointer
+// is enabled.
 //
 // WARNING: do not use or alias p after fn returns,
 // only reading and copying p are considered safe!
 func (i *Iterator[S]) ViewPointer(fn func(p []byte)) {
-	i.ScanStack(func(keyIndex, keyEnd, arrayIndex int) {
-		if keyIndex != -1 {
+	if i.trackPointer {
+		// Only the reference token of the current value is appended
+		// to the tracked pointer of its parent.
+		i.pointer = i.pointer[:0]
+		if l := len(i.stack); l > 0 {
+			i.pointer = append(i.pointer[:i.pointerEnds[l-1]], '/')
+			if i.keyIndex != -1 {
+				i.pointer = keyescape.Append(
+					i.pointer, i.src[i.keyIndex+1:i.keyIndexEnd-1],
+				)
+			} else {
+				i.pointer = strconv.AppendInt(i.pointer, int64(i.arrayIndex), 10)
+			}
+		}
+		fn(i.pointer)
+		return
+	}
+	i.scanStack(func(key S, arrayIndex int) {
+		if arrayIndex == -1 {
 			// Object key
 			i.pointer = append(i.pointer, '/')
-			i.pointer = keyescape.Append(i.pointer, i.src[keyIndex+1:keyEnd-1])
+			i.pointer = keyescape.Append(i.pointer, key[1:len(key)-1])
 			return
 		}
 		// Array index
@@ -158,6 +500,26 @@ func (i *Iterator[S]) ViewPointer(fn func(p []byte)) {
 	i.pointer = i.pointer[:0]
 }
 
+// markPointer records the pointer of the object or array that was
+// pushed onto the stack given that trackPointer is enabled.
+// The pointers of the objects and arrays on the stack share the buffer,
+// the pointer of the object or array at level l is pointer[:pointerEnds[l]].
+func (i *Iterator[S]) markPointer() {
+	l := len(i.stack) - 1
+	i.pointer = i.pointer[:0]
+	if l > 0 {
+		i.pointer = append(i.pointer[:i.pointerEnds[l-1]], '/')
+		if key := i.stackKey(l); len(key) > 0 {
+			i.pointer = keyescape.Append(i.pointer, key[1:len(key)-1])
+		} else {
+			i.pointer = strconv.AppendInt(
+				i.pointer, int64(i.stack[l-1].Len-1), 10,
+			)
+		}
+	}
+	i.pointerEnds = append(i.pointerEnds[:l], len(i.pointer))
+}
+
 func (i *Iterator[S]) getError(c ErrorCode) Error[S] {
 	return Error[S]{
 		Code:  c,
@@ -179,6 +541,15 @@ type Error[S ~string | ~[]byte] struct {
 
 	// Code indicates the type of the error.
 	Code ErrorCode
+
+	// offset is the index of Src[0] in the source when Src is only
+	// the buffered part of a stream.
+	offset int
+
+	// err is the cause of the error, such as ctx.Err()
+	// for ErrorCodeCanceled or the error returned by the reader
+	// for ErrorCodeRead, if any.
+	err error
 }
 
 var _ error = Error[string]{}
@@ -186,17 +557,29 @@ var _ error = Error[string]{}
 // IsErr returns true if there is an error, otherwise returns false.
 func (e Error[S]) IsErr() bool { return e.Code != 0 }
 
+// Unwrap returns the cause of the error if any, such as
+// context.Canceled or context.DeadlineExceeded for ErrorCodeCanceled
+// or the error returned by the reader for ErrorCodeRead.
+func (e Error[S]) Unwrap() error { return e.err }
+
 // Error stringifies the error implementing the built-in error interface.
 // Calling Error should be avoided in performance-critical code as it
 // relies on dynamic memory allocation.
 func (e Error[S]) Error() string {
-	if e.Index < len(e.Src) {
+	if e.err != nil {
+		return errorMessage(e.Code, e.Index, -1) + ": " + e.err.Error()
+	}
+	x := e.Index - e.offset
+	if x < 0 {
+		return errorMessage(e.Code, e.Index, -1)
+	}
+	if x < len(e.Src) {
 		var r rune
-		switch x := any(e.Src).(type) {
+		switch s := any(e.Src).(type) {
 		case string:
-			r, _ = utf8.DecodeRuneInString(x[e.Index:])
+			r, _ = utf8.DecodeRuneInString(s[x:])
 		case []byte:
-			r, _ = utf8.DecodeRune(x[e.Index:])
+			r, _ = utf8.DecodeRune(s[x:])
 		}
 		return errorMessage(e.Code, e.Index, r)
 	}
@@ -210,6 +593,10 @@ func reset[S ~string | ~[]byte](i *Iterator[S]) {
 	i.keyIndex, i.keyIndexEnd = -1, -1
 	i.valueIndexEnd = -1
 	i.arrayIndex = 0
+	i.skip = false
+	i.end, i.len = false, -1
+	i.matchCache.levels = i.matchCache.levels[:0]
+	i.routeCache.levels = i.routeCache.levels[:0]
 }
 
 // ErrorCode defines the error type.
@@ -236,8 +623,74 @@ const (
 
 	// ErrorCodeCallback indicates return of true from the callback function.
 	ErrorCodeCallback
+
+	// ErrorCodeNumberNotRepresentable indicates the encounter of a number
+	// that isn't exactly representable by the type required by
+	// ValidatorOptions.Numbers.
+	ErrorCodeNumberNotRepresentable
+
+	// ErrorCodeInvalidCheckpoint indicates a checkpoint
+	// that doesn't fit the source.
+	ErrorCodeInvalidCheckpoint
+
+	// ErrorCodeCanceled indicates that scanning or validation was stopped
+	// because the context passed to ScanContext or ValidateContext
+	// was canceled or its deadline was exceeded.
+	ErrorCodeCanceled
+
+	// ErrorCodeTooLarge indicates a source larger than 4 GiB
+	// passed to BuildIndex.
+	ErrorCodeTooLarge
+
+	// ErrorCodeRead indicates an error returned by the reader passed to
+	// ValidateReader. Unwrap returns the error returned by the reader.
+	ErrorCodeRead
 )
 
+// Action defines the action a callback requests the scanner to take
+// after it returns.
+type Action int8
+
+const (
+	// ActionContinue resumes scanning.
+	ActionContinue Action = iota
+
+	// ActionSkipChildren skips all member and element values of the current
+	// object or array without invoking the callback for any of them.
+	// Skipped values are still validated.
+	// ActionSkipChildren is equivalent to ActionContinue for all other value types.
+	ActionSkipChildren
+
+	// ActionStop stops scanning and makes the scanner return ErrorCodeCallback.
+	ActionStop
+)
+
+// NumberKind defines the notation of a JSON number value.
+type NumberKind int8
+
+// JSON number kinds
+const (
+	_ NumberKind = iota
+
+	// NumberKindInteger is a number without a fraction
+	// and an exponent part, such as 42 or -7.
+	NumberKindInteger
+
+	// NumberKindFloat is a number with either a fraction or
+	// an exponent part or both, such as 1.0, 1e3 or -1.5E-3.
+	NumberKindFloat
+)
+
+func (k NumberKind) String() string {
+	switch k {
+	case NumberKindInteger:
+		return "integer"
+	case NumberKindFloat:
+		return "float"
+	}
+	return ""
+}
+
 // ValueType defines a JSON value type
 type ValueType int8
 
@@ -288,9 +741,23 @@ func errorMessage(c ErrorCode, index int, atIndex rune) string {
 		errMsg = "illegal control character"
 	case ErrorCodeCallback:
 		errMsg = "callback error"
+	case ErrorCodeNumberNotRepresentable:
+		errMsg = "number not representable"
+	case ErrorCodeInvalidCheckpoint:
+		return fmt.Sprintf("error at index %d: invalid checkpoint", index)
+	case ErrorCodeCanceled:
+		return fmt.Sprintf("error at index %d: canceled", index)
+	case ErrorCodeTooLarge:
+		return fmt.Sprintf("error at index %d: source too large", index)
+	case ErrorCodeRead:
+		return fmt.Sprintf("error at index %d: read error", index)
 	default:
 		return ""
 	}
+	if atIndex < 0 {
+		// The character at index is unknown.
+		return fmt.Sprintf("error at index %d: %s", index, errMsg)
+	}
 	if atIndex < 0x20 {
 		return fmt.Sprintf(
 			"error at index %d (0x%x): %s",
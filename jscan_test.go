@@ -77,6 +77,12 @@ func testStrictOK[S ~string | ~[]byte](t *testing.T, input S) {
 			)
 			require.False(t, err.IsErr())
 		})
+		t.Run("Reader", func(t *testing.T) {
+			r := jscan.NewReader(input)
+			for r.Next() {
+			}
+			require.False(t, r.Err().IsErr())
+		})
 	})
 }
 
@@ -140,6 +146,12 @@ func testStrictErr[S ~string | ~[]byte](t *testing.T, input S) {
 		t.Run("Validate", func(t *testing.T) {
 			require.True(t, jscan.Validate[S](input).IsErr())
 		})
+		t.Run("Reader", func(t *testing.T) {
+			r := jscan.NewReader(input)
+			for r.Next() {
+			}
+			require.True(t, r.Err().IsErr())
+		})
 	})
 }
 
@@ -654,6 +666,14 @@ func testScan[S ~string | ~[]byte](t *testing.T, td ScanTest) {
 			err := p.Scan(S(td.input), check(t))
 			require.False(t, err.IsErr(), "unexpected error: %s", err)
 		})
+		t.Run("ParserScanTrackPointer", func(t *testing.T) {
+			j = 0
+			p := jscan.NewParserWithOptions[S](64, jscan.ParserOptions{
+				TrackPointer: true,
+			})
+			err := p.Scan(S(td.input), check(t))
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+		})
 	})
 }
 
@@ -1609,3 +1629,447 @@ func TestDerivedTypes(t *testing.T) {
 		})
 	})
 }
+
+func TestScanAction(t *testing.T) {
+	const input = `{"a":{"x":[1,2,{"y":3}]},"b":[true,[null]],"c":"d","e":{}}`
+	testScanAction(t, string(input))
+	testScanAction(t, []byte(input))
+}
+
+func testScanAction[S ~string | ~[]byte](t *testing.T, input S) {
+	t.Run(testDataType(input), func(t *testing.T) {
+		t.Run("SkipChildren", func(t *testing.T) {
+			var pointers []string
+			err := jscan.ScanAction(input, func(i *jscan.Iterator[S]) jscan.Action {
+				pointers = append(pointers, string(i.Pointer()))
+				if i.Level() == 1 {
+					return jscan.ActionSkipChildren
+				}
+				return jscan.ActionContinue
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, []string{"", "/a", "/b", "/c", "/e"}, pointers)
+		})
+
+		t.Run("ParserSkipChildren", func(t *testing.T) {
+			var pointers []string
+			p := jscan.NewParser[S](64)
+			err := p.ScanAction(input, func(i *jscan.Iterator[S]) jscan.Action {
+				pointers = append(pointers, string(i.Pointer()))
+				if string(i.Key()) == `"x"` {
+					return jscan.ActionSkipChildren
+				}
+				return jscan.ActionContinue
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, []string{
+				"", "/a", "/a/x", "/b", "/b/0", "/b/1", "/b/1/0", "/c", "/e",
+			}, pointers)
+		})
+
+		t.Run("Stop", func(t *testing.T) {
+			c := 0
+			err := jscan.ScanAction(input, func(i *jscan.Iterator[S]) jscan.Action {
+				c++
+				return jscan.ActionStop
+			})
+			require.Equal(t, 1, c)
+			require.Equal(t, jscan.ErrorCodeCallback, err.Code)
+			require.Equal(t, 0, err.Index)
+		})
+
+		t.Run("ScanOneSkipChildren", func(t *testing.T) {
+			c := 0
+			trailing, err := jscan.ScanOneAction(
+				S(string(input)+`[1]`),
+				func(i *jscan.Iterator[S]) jscan.Action {
+					c++
+					return jscan.ActionSkipChildren
+				},
+			)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, 1, c)
+			require.Equal(t, `[1]`, string(trailing))
+		})
+
+		t.Run("ErrorInSkipped", func(t *testing.T) {
+			in := S(`{"a":[1,{"b":tru}]}`)
+			err := jscan.ScanAction(in, func(i *jscan.Iterator[S]) jscan.Action {
+				return jscan.ActionSkipChildren
+			})
+			require.True(t, err.IsErr())
+			require.Equal(t, jscan.ErrorCodeUnexpectedToken, err.Code)
+			require.Equal(t, len(`{"a":[1,{"b":`), err.Index)
+			require.Equal(t, string(in), string(err.Src))
+		})
+	})
+}
+
+func TestCaptureRaw(t *testing.T) {
+	const input = `{"a":{"x":[1,2,{"y":3}]},"b":[ true , [null] ],"c":"d","e":{}}`
+	testCaptureRaw(t, string(input))
+	testCaptureRaw(t, []byte(input))
+}
+
+func testCaptureRaw[S ~string | ~[]byte](t *testing.T, input S) {
+	t.Run(testDataType(input), func(t *testing.T) {
+		type R struct{ Pointer, Raw string }
+
+		t.Run("Scan", func(t *testing.T) {
+			var records []R
+			err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+				r := R{Pointer: string(i.Pointer())}
+				if i.Level() == 1 {
+					r.Raw = string(i.CaptureRaw())
+					// Repeated calls must return the same value.
+					require.Equal(t, r.Raw, string(i.CaptureRaw()))
+					require.Equal(t, r.Raw, string(i.Value()))
+				}
+				records = append(records, r)
+				return false
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, []R{
+				{"", ""},
+				{"/a", `{"x":[1,2,{"y":3}]}`},
+				{"/b", `[ true , [null] ]`},
+				{"/c", `"d"`},
+				{"/e", `{}`},
+			}, records)
+		})
+
+		t.Run("EndEvents", func(t *testing.T) {
+			var records []EndRecord
+			p := jscan.NewParserWithOptions[S](64, jscan.ParserOptions{
+				EndEvents: true,
+			})
+			err := p.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+				if !i.IsEnd() && string(i.Key()) == `"x"` {
+					require.Equal(t, `[1,2,{"y":3}]`, string(i.CaptureRaw()))
+				}
+				records = append(records, EndRecord{
+					End:     i.IsEnd(),
+					Pointer: string(i.Pointer()),
+					Len:     i.Len(),
+				})
+				return false
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, []EndRecord{
+				{End: false, Pointer: "", Len: -1},
+				{End: false, Pointer: "/a", Len: -1},
+				{End: false, Pointer: "/a/x", Len: -1},
+				{End: true, Pointer: "/a/x", Len: -1},
+				{End: true, Pointer: "/a", Len: 1},
+			}, records[:5])
+		})
+
+		t.Run("Root", func(t *testing.T) {
+			var raw string
+			err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+				raw = string(i.CaptureRaw())
+				return false
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, string(input), raw)
+		})
+
+		t.Run("Error", func(t *testing.T) {
+			in := S(`{"a":[1,{"b":tru}],"c":0}`)
+			c := 0
+			err := jscan.Scan(in, func(i *jscan.Iterator[S]) (err bool) {
+				c++
+				if i.ValueType() == jscan.ValueTypeArray {
+					require.Zero(t, string(i.CaptureRaw()))
+				}
+				return false
+			})
+			require.Equal(t, 2, c)
+			require.True(t, err.IsErr())
+			require.Equal(t, jscan.ErrorCodeUnexpectedToken, err.Code)
+			require.Equal(t, len(`{"a":[1,{"b":`), err.Index)
+			require.Equal(t, string(in), string(err.Src))
+		})
+	})
+}
+
+func TestKeyEquals(t *testing.T) {
+	const input = `{"name":0,"Na\u006De":1,"\"q":2,"\\":3,"\u00c4":4,"":5,"x":[6]}`
+	testKeyEquals(t, string(input))
+	testKeyEquals(t, []byte(input))
+}
+
+func testKeyEquals[S ~string | ~[]byte](t *testing.T, input S) {
+	t.Run(testDataType(input), func(t *testing.T) {
+		type R struct {
+			Key               string
+			Equals, EqualFold []string
+		}
+		candidates := []string{"name", "NAME", `"q`, `\\"q`, `\`, `\\`, "Ä", "ä", ""}
+		var records []R
+		err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+			r := R{Key: string(i.Key())}
+			for _, c := range candidates {
+				if i.KeyEquals(c) {
+					r.Equals = append(r.Equals, c)
+				}
+				if i.KeyEqualFold(c) {
+					r.EqualFold = append(r.EqualFold, c)
+				}
+			}
+			records = append(records, r)
+			return false
+		})
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+		require.Equal(t, []R{
+			{Key: ""},
+			{`"name"`, []string{"name"}, []string{"name", "NAME"}},
+			{`"Na\u006De"`, nil, []string{"name", "NAME"}},
+			{`"\"q"`, []string{`"q`}, []string{`"q`}},
+			{`"\\"`, []string{`\`}, []string{`\`}},
+			{`"\u00c4"`, []string{"Ä"}, []string{"Ä", "ä"}},
+			{`""`, []string{""}, []string{""}},
+			{`"x"`, nil, nil},
+			{Key: ""},
+		}, records)
+
+		allocs := testing.AllocsPerRun(16, func() {
+			jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+				i.KeyEquals("name")
+				i.KeyEquals("Ä")
+				i.KeyEqualFold("NAME")
+				return false
+			})
+		})
+		require.Zero(t, allocs)
+	})
+}
+
+func TestAppendUnescaped(t *testing.T) {
+	const input = `{"plain":"text","es\u0063aped":"\"q\\/\/\b\f\n\r\t\u00e4\ud83d\ude00","lone":"\ud83dx",` +
+		`"n":-1.5,"b":[true,null]}`
+	testAppendUnescaped(t, string(input))
+	testAppendUnescaped(t, []byte(input))
+}
+
+func testAppendUnescaped[S ~string | ~[]byte](t *testing.T, input S) {
+	t.Run(testDataType(input), func(t *testing.T) {
+		type R struct {
+			Key, Value string
+			Escapes    bool
+		}
+		var records []R
+		err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+			records = append(records, R{
+				Key:     string(i.AppendUnescapedKey([]byte("k:"))),
+				Value:   string(i.AppendUnescapedValue([]byte("v:"))),
+				Escapes: i.ValueHasEscapes(),
+			})
+			return false
+		})
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+		require.Equal(t, []R{
+			{"k:", "v:", false},
+			{"k:plain", "v:text", false},
+			{"k:escaped", "v:\"q\\//\b\f\n\r\tä😀", true},
+			{"k:lone", "v:\uFFFDx", true},
+			{"k:n", "v:-1.5", false},
+			{"k:b", "v:", false},
+			{"k:", "v:true", false},
+			{"k:", "v:null", false},
+		}, records)
+
+		allocs := testing.AllocsPerRun(16, func() {
+			buf := make([]byte, 0, 64)
+			jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+				buf = i.AppendUnescapedKey(buf[:0])
+				buf = i.AppendUnescapedValue(buf[:0])
+				return false
+			})
+		})
+		require.LessOrEqual(t, allocs, float64(1))
+	})
+}
+
+type EndRecord struct {
+	End        bool
+	ValueType  jscan.ValueType
+	Pointer    string
+	Level      int
+	ArrayIndex int
+	Len        int
+	Value      string
+}
+
+func TestEndEvents(t *testing.T) {
+	const input = `{"a":[1,{}],"b":{"c":[ ]},"d":[[2]]}`
+	testEndEvents(t, string(input))
+	testEndEvents(t, []byte(input))
+}
+
+func testEndEvents[S ~string | ~[]byte](t *testing.T, input S) {
+	t.Run(testDataType(input), func(t *testing.T) {
+		testEndEventsOptions(t, input, jscan.ParserOptions{EndEvents: true})
+	})
+	t.Run(testDataType(input)+"_track_pointer", func(t *testing.T) {
+		testEndEventsOptions(t, input, jscan.ParserOptions{
+			EndEvents:    true,
+			TrackPointer: true,
+		})
+	})
+}
+
+func testEndEventsOptions[S ~string | ~[]byte](
+	t *testing.T, input S, o jscan.ParserOptions,
+) {
+	p := jscan.NewParserWithOptions[S](64, o)
+
+	t.Run("Scan", func(t *testing.T) {
+		var records []EndRecord
+		err := p.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+			r := EndRecord{
+				End:        i.IsEnd(),
+				ValueType:  i.ValueType(),
+				Pointer:    string(i.Pointer()),
+				Level:      i.Level(),
+				ArrayIndex: i.ArrayIndex(),
+				Len:        i.Len(),
+			}
+			if i.IsEnd() {
+				r.Value = string(i.Value())
+				require.Equal(t, r.Value, string(
+					input[i.ValueIndex():i.ValueIndexEnd()],
+				))
+			}
+			records = append(records, r)
+			return false
+		})
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+
+		O, A, N := jscan.ValueTypeObject, jscan.ValueTypeArray, jscan.ValueTypeNumber
+		require.Equal(t, []EndRecord{
+			{false, O, "", 0, -1, -1, ""},
+			{false, A, "/a", 1, -1, -1, ""},
+			{false, N, "/a/0", 2, 0, -1, ""},
+			{false, O, "/a/1", 2, 1, -1, ""},
+			{true, O, "/a/1", 2, 1, 0, "{}"},
+			{true, A, "/a", 1, -1, 2, "[1,{}]"},
+			{false, O, "/b", 1, -1, -1, ""},
+			{false, A, "/b/c", 2, -1, -1, ""},
+			{true, A, "/b/c", 2, -1, 0, "[ ]"},
+			{true, O, "/b", 1, -1, 1, `{"c":[ ]}`},
+			{false, A, "/d", 1, -1, -1, ""},
+			{false, A, "/d/0", 2, 0, -1, ""},
+			{false, N, "/d/0/0", 3, 0, -1, ""},
+			{true, A, "/d/0", 2, 0, 1, "[2]"},
+			{true, A, "/d", 1, -1, 1, "[[2]]"},
+			{true, O, "", 0, -1, 3, string(input)},
+		}, records)
+	})
+
+	t.Run("SkipChildren", func(t *testing.T) {
+		var records []EndRecord
+		err := p.ScanAction(input, func(i *jscan.Iterator[S]) jscan.Action {
+			records = append(records, EndRecord{
+				End:       i.IsEnd(),
+				ValueType: i.ValueType(),
+				Pointer:   string(i.Pointer()),
+				Len:       i.Len(),
+			})
+			if i.Level() > 0 {
+				return jscan.ActionSkipChildren
+			}
+			return jscan.ActionContinue
+		})
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+
+		O, A := jscan.ValueTypeObject, jscan.ValueTypeArray
+		require.Equal(t, []EndRecord{
+			{End: false, ValueType: O, Pointer: "", Len: -1},
+			{End: false, ValueType: A, Pointer: "/a", Len: -1},
+			{End: true, ValueType: A, Pointer: "/a", Len: -1},
+			{End: false, ValueType: O, Pointer: "/b", Len: -1},
+			{End: true, ValueType: O, Pointer: "/b", Len: -1},
+			{End: false, ValueType: A, Pointer: "/d", Len: -1},
+			{End: true, ValueType: A, Pointer: "/d", Len: -1},
+			{End: true, ValueType: O, Pointer: "", Len: 3},
+		}, records)
+	})
+
+	t.Run("ErrorCallback", func(t *testing.T) {
+		err := p.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+			return i.IsEnd()
+		})
+		require.True(t, err.IsErr())
+		require.Equal(t, jscan.ErrorCodeCallback, err.Code)
+		require.Equal(t, len(`{"a":[1,{`), err.Index)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+			require.False(t, i.IsEnd())
+			require.Equal(t, -1, i.Len())
+			return false
+		})
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+	})
+}
+
+func TestFrame(t *testing.T) {
+	const input = `{"a":[0,{"b":[true]}]}`
+	testFrame(t, string(input))
+	testFrame(t, []byte(input))
+}
+
+func testFrame[S ~string | ~[]byte](t *testing.T, input S) {
+	for _, endEvents := range []bool{false, true} {
+		// Members of objects are only counted with end events enabled.
+		objLen := 0
+		if endEvents {
+			objLen = 1
+		}
+		name := fmt.Sprintf("%s/endEvents=%t", testDataType(input), endEvents)
+		t.Run(name, func(t *testing.T) {
+			c := 0
+			p := jscan.NewParserWithOptions[S](64, jscan.ParserOptions{
+				EndEvents: endEvents,
+			})
+			err := p.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+				if i.ValueType() != jscan.ValueTypeTrue {
+					return false
+				}
+				c++
+				require.Equal(t, 4, i.Level())
+
+				type F struct {
+					ValueType  jscan.ValueType
+					Index      int
+					Key        string
+					ArrayIndex int
+					Len        int
+				}
+				var frames []F
+				for l := 0; l < i.Level(); l++ {
+					f := i.Frame(l)
+					frames = append(frames, F{
+						f.ValueType, f.Index, string(f.Key()), f.ArrayIndex, f.Len,
+					})
+					if f.KeyIndex != -1 {
+						require.Equal(t, string(f.Key()),
+							string(input[f.KeyIndex:f.KeyIndexEnd]))
+					}
+				}
+				require.Equal(t, []F{
+					{jscan.ValueTypeObject, 0, "", -1, objLen},
+					{jscan.ValueTypeArray, len(`{"a":`), `"a"`, -1, 2},
+					{jscan.ValueTypeObject, len(`{"a":[0,`), "", 1, objLen},
+					{jscan.ValueTypeArray, len(`{"a":[0,{"b":`), `"b"`, -1, 1},
+				}, frames)
+				require.Panics(t, func() { i.Frame(4) })
+				return false
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, 1, c)
+		})
+	}
+}
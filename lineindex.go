@@ -0,0 +1,274 @@
+package jscan
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strings"
+
+	"github.com/romshark/jscan/v2/internal/strfind"
+	"github.com/romshark/jscan/v2/internal/unescape"
+)
+
+// LineIndex is an index of the records of an NDJSON (newline delimited
+// JSON) source built by BuildLineIndex which allows reading a record
+// without scanning the records preceding it.
+// Records are the non-blank lines of the source, hence record numbers
+// differ from line numbers if the source contains blank lines,
+// see Line and LineRecord.
+// Records can optionally be keyed by the value at a JSON pointer for
+// lookup by key.
+//
+// A LineIndex is immutable and safe for concurrent use.
+type LineIndex struct {
+	srcLen int
+	crc    uint32
+
+	// start and end are the offsets of each record excluding the newline.
+	start, end []int
+
+	// line is the line number of each record.
+	line []int
+
+	// keyed is nil if the records aren't keyed.
+	keyed []bool
+	keys  []string
+
+	// sorted holds the keyed records in the order of their keys.
+	sorted []int
+}
+
+// BuildLineIndex validates every record of the NDJSON source s and
+// returns its line index. If keyPointer isn't empty, every record is keyed
+// by the value at the JSON pointer keyPointer. The keys of strings are
+// their unescaped values while the keys of all other values are their
+// source representation, such as `42` or `{"a":true}`.
+// The key pointer can be a pattern, in which case the first matching
+// value is used (see Patterns).
+// Returns an error if keyPointer is invalid and Error[S] if a record
+// is invalid JSON.
+func BuildLineIndex[S ~string | ~[]byte](
+	s S, keyPointer string,
+) (*LineIndex, error) {
+	x := &LineIndex{srcLen: len(s), crc: checksum(s)}
+
+	var (
+		p      = NewParser[S](DefaultStackSizeIterator)
+		v      = NewValidator[S](DefaultStackSizeValidator)
+		r      *Router[S]
+		key    string
+		hasKey bool
+	)
+	if keyPointer != "" {
+		var err error
+		r, err = NewRouter(map[string]func(*Iterator[S]) bool{
+			keyPointer: func(i *Iterator[S]) (err bool) {
+				if hasKey {
+					return false
+				}
+				switch i.valueType {
+				case ValueTypeString:
+					key = string(unescape.Append(nil, i.stringContent()))
+				case ValueTypeObject, ValueTypeArray:
+					key = string(i.CaptureRaw())
+				default:
+					key = string(i.Value())
+				}
+				hasKey = true
+				return false
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		x.keyed = []bool{}
+	}
+
+	for start, lineNum := 0, 1; start < len(s); lineNum++ {
+		end := indexByte(s[start:], '\n')
+		next := start + end + 1
+		if end == -1 {
+			end, next = len(s)-start, len(s)
+		}
+		end += start
+		line := s[start:end]
+		if t, illegalChar := strfind.EndOfWhitespaceSeq(line); !illegalChar &&
+			len(t) == 0 {
+			// Skip blank lines.
+			start = next
+			continue
+		}
+
+		var err Error[S]
+		if r != nil {
+			key, hasKey = "", false
+			err = r.ScanParser(p, line)
+		} else {
+			err = v.Validate(line)
+		}
+		if err.IsErr() {
+			err.Src, err.Index = s, start+err.Index
+			return nil, err
+		}
+		x.start, x.end = append(x.start, start), append(x.end, end)
+		x.line = append(x.line, lineNum)
+		if r != nil {
+			x.keyed, x.keys = append(x.keyed, hasKey), append(x.keys, key)
+		}
+		start = next
+	}
+	x.sortKeys()
+	return x, nil
+}
+
+// indexByte returns the index of the first c in s or -1 if there is none.
+func indexByte[S ~string | ~[]byte](s S, c byte) int {
+	switch x := any(s).(type) {
+	case string:
+		return strings.IndexByte(x, c)
+	case []byte:
+		return bytes.IndexByte(x, c)
+	}
+	return strings.IndexByte(string(s), c)
+}
+
+// sortKeys initializes sorted.
+func (x *LineIndex) sortKeys() {
+	if x.keyed == nil {
+		return
+	}
+	x.sorted = make([]int, 0, len(x.keys))
+	for n, ok := range x.keyed {
+		if ok {
+			x.sorted = append(x.sorted, n)
+		}
+	}
+	sort.SliceStable(x.sorted, func(a, b int) bool {
+		return x.keys[x.sorted[a]] < x.keys[x.sorted[b]]
+	})
+}
+
+// Len returns the number of records.
+func (x *LineIndex) Len() int { return len(x.start) }
+
+// Record returns the start and end offset of record n in the source
+// excluding the newline.
+func (x *LineIndex) Record(n int) (start, end int) { return x.start[n], x.end[n] }
+
+// Line returns the line number of record n starting at 1.
+func (x *LineIndex) Line(n int) int { return x.line[n] }
+
+// LineRecord returns the number of the record at the given line number
+// or false if the line is blank or out of range.
+func (x *LineIndex) LineRecord(line int) (n int, ok bool) {
+	n = sort.SearchInts(x.line, line)
+	if n == len(x.line) || x.line[n] != line {
+		return 0, false
+	}
+	return n, true
+}
+
+// Key returns the key of record n or false if either the records
+// aren't keyed or record n has no value at the key pointer.
+func (x *LineIndex) Key(n int) (key string, ok bool) {
+	if x.keyed == nil || !x.keyed[n] {
+		return "", false
+	}
+	return x.keys[n], true
+}
+
+// Lookup returns the records with the given key in ascending order.
+func (x *LineIndex) Lookup(key string) []int {
+	j := sort.Search(len(x.sorted), func(j int) bool {
+		return x.keys[x.sorted[j]] >= key
+	})
+	k := j
+	for k < len(x.sorted) && x.keys[x.sorted[k]] == key {
+		k++
+	}
+	if j == k {
+		return nil
+	}
+	return append([]int(nil), x.sorted[j:k]...)
+}
+
+// MarshalBinary encodes x into a compact binary form that LoadLineIndex
+// can restore x from without rescanning the source.
+// The encoding contains a checksum of the source but not the source itself.
+func (x *LineIndex) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 16+len(x.start)*4)
+	b = appendIndexHeader(b, indexFormatLineIndex, x.srcLen, x.crc)
+	b = binary.AppendUvarint(b, uint64(len(x.start)))
+	prevEnd, prevLine := 0, 0
+	for n := range x.start {
+		// The number of blank lines preceding the record.
+		b = binary.AppendUvarint(b, uint64(x.line[n]-prevLine-1))
+		prevLine = x.line[n]
+		b = binary.AppendUvarint(b, uint64(x.start[n]-prevEnd))
+		b = binary.AppendUvarint(b, uint64(x.end[n]-x.start[n]))
+		prevEnd = x.end[n]
+	}
+	if x.keyed == nil {
+		return append(b, 0), nil
+	}
+	b = append(b, 1)
+	for n, ok := range x.keyed {
+		// Zero indicates no key.
+		if !ok {
+			b = append(b, 0)
+			continue
+		}
+		b = binary.AppendUvarint(b, uint64(len(x.keys[n])+1))
+		b = append(b, x.keys[n]...)
+	}
+	return b, nil
+}
+
+// LoadLineIndex restores the line index of src from data encoded by
+// (*LineIndex).MarshalBinary.
+// Returns ErrInvalidIndex if data isn't a valid binary encoded line index
+// and ErrIndexMismatch if the index wasn't built for src.
+// Verifying the checksum of src is much faster than rebuilding the index.
+func LoadLineIndex[S ~string | ~[]byte](src S, data []byte) (*LineIndex, error) {
+	d := &indexDecoder{b: data}
+	crc, err := readIndexHeader(d, indexFormatLineIndex, src)
+	if err != nil {
+		return nil, err
+	}
+	x := &LineIndex{srcLen: len(src), crc: crc}
+	// Every record is encoded by at least 3 bytes.
+	l := d.count(3)
+	x.start, x.end, x.line = make([]int, l), make([]int, l), make([]int, l)
+	prevEnd, prevLine := 0, 0
+	for n := 0; n < l; n++ {
+		x.line[n] = prevLine + 1 + d.uvarint()
+		if x.line[n] <= prevLine {
+			// Overflow.
+			return nil, ErrInvalidIndex
+		}
+		prevLine = x.line[n]
+		x.start[n] = prevEnd + d.uvarint()
+		x.end[n] = x.start[n] + d.uvarint()
+		if x.start[n] < prevEnd || x.end[n] <= x.start[n] ||
+			x.end[n] > x.srcLen {
+			return nil, ErrInvalidIndex
+		}
+		prevEnd = x.end[n]
+	}
+	switch flag := d.bytes(1); {
+	case flag == nil || flag[0] > 1:
+		return nil, ErrInvalidIndex
+	case flag[0] == 1:
+		x.keyed, x.keys = make([]bool, l), make([]string, l)
+		for n := 0; n < l; n++ {
+			if k := d.uvarint(); k > 0 {
+				x.keyed[n], x.keys[n] = true, string(d.bytes(k-1))
+			}
+		}
+	}
+	if d.err != nil || len(d.b) > 0 {
+		return nil, ErrInvalidIndex
+	}
+	x.sortKeys()
+	return x, nil
+}
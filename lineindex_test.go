@@ -0,0 +1,140 @@
+package jscan_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+const testNDJSON = `{"id":"b","v":1}
+{"id":"a","v":[2]}
+
+  {"v":3}` + "\r" + `
+{"id":"b!","v":4}
+{"id":{"x":1},"v":5}
+{"id":"a","v":6}
+`
+
+func TestBuildLineIndex(t *testing.T) {
+	x, err := jscan.BuildLineIndex(testNDJSON, "")
+	require.NoError(t, err)
+	require.Equal(t, 6, x.Len())
+
+	var records []string
+	var lines []int
+	for n := 0; n < x.Len(); n++ {
+		start, end := x.Record(n)
+		records = append(records, testNDJSON[start:end])
+		lines = append(lines, x.Line(n))
+		_, ok := x.Key(n)
+		require.False(t, ok)
+	}
+	// Line 3 is blank and hence not a record.
+	require.Equal(t, []int{1, 2, 4, 5, 6, 7}, lines)
+	for line, expect := range []int{-1, 0, 1, -1, 2, 3, 4, 5, -1} {
+		n, ok := x.LineRecord(line)
+		require.Equal(t, expect != -1, ok, "line %d", line)
+		if ok {
+			require.Equal(t, expect, n, "line %d", line)
+		}
+	}
+	require.Equal(t, []string{
+		`{"id":"b","v":1}`,
+		`{"id":"a","v":[2]}`,
+		"  {\"v\":3}\r",
+		`{"id":"b!","v":4}`,
+		`{"id":{"x":1},"v":5}`,
+		`{"id":"a","v":6}`,
+	}, records)
+	require.Nil(t, x.Lookup("a"))
+}
+
+func TestBuildLineIndexKeyed(t *testing.T) {
+	x, err := jscan.BuildLineIndex([]byte(testNDJSON), "/id")
+	require.NoError(t, err)
+	require.Equal(t, 6, x.Len())
+
+	var keys []string
+	for n := 0; n < x.Len(); n++ {
+		k, ok := x.Key(n)
+		require.Equal(t, n != 2, ok)
+		keys = append(keys, k)
+	}
+	require.Equal(t, []string{"b", "a", "", "b!", `{"x":1}`, "a"}, keys)
+
+	require.Equal(t, []int{1, 5}, x.Lookup("a"))
+	require.Equal(t, []int{0}, x.Lookup("b"))
+	require.Equal(t, []int{3}, x.Lookup("b!"))
+	require.Equal(t, []int{4}, x.Lookup(`{"x":1}`))
+	require.Nil(t, x.Lookup(""))
+	require.Nil(t, x.Lookup("c"))
+
+	x, err = jscan.BuildLineIndex(testNDJSON, "/v/0")
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, x.Lookup("2"))
+}
+
+func TestBuildLineIndexErr(t *testing.T) {
+	_, err := jscan.BuildLineIndex("{}\n{\"a\":}\n", "")
+	require.Equal(t, jscan.Error[string]{
+		Src: "{}\n{\"a\":}\n", Index: 8, Code: jscan.ErrorCodeUnexpectedToken,
+	}, err)
+
+	_, err = jscan.BuildLineIndex("{}\n[1,\n2]\n", "/a")
+	require.Equal(t, jscan.Error[string]{
+		Src: "{}\n[1,\n2]\n", Index: 6, Code: jscan.ErrorCodeUnexpectedEOF,
+	}, err)
+
+	_, err = jscan.BuildLineIndex("{}", "a")
+	require.Error(t, err)
+}
+
+func TestLineIndexMarshalBinary(t *testing.T) {
+	for _, keyPointer := range []string{"", "/id"} {
+		x, err := jscan.BuildLineIndex(testNDJSON, keyPointer)
+		require.NoError(t, err)
+		b, err := x.MarshalBinary()
+		require.NoError(t, err)
+
+		y, err := jscan.LoadLineIndex(testNDJSON, b)
+		require.NoError(t, err)
+		require.Equal(t, x, y)
+		yb, err := jscan.LoadLineIndex([]byte(testNDJSON), b)
+		require.NoError(t, err)
+		require.Equal(t, x, yb)
+
+		for _, src := range []string{
+			testNDJSON + "\n", strings.Replace(testNDJSON, "6", "7", 1),
+		} {
+			_, err = jscan.LoadLineIndex(src, b)
+			require.ErrorIs(t, err, jscan.ErrIndexMismatch)
+		}
+
+		_, err = jscan.LoadLineIndex(testNDJSON, b[:len(b)-1])
+		require.ErrorIs(t, err, jscan.ErrInvalidIndex)
+		_, err = jscan.LoadLineIndex(testNDJSON, append(b, 0))
+		require.ErrorIs(t, err, jscan.ErrInvalidIndex)
+		xb, err := x.MarshalBinary()
+		require.NoError(t, err)
+		_, err = jscan.LoadIndex(testNDJSON, xb)
+		require.ErrorIs(t, err, jscan.ErrInvalidIndex)
+
+		// Corrupting any byte must never cause a panic.
+		for j := 0; j < len(b); j++ {
+			for _, c := range []byte{0, 1, 0x7f, 0xff} {
+				d := append([]byte(nil), b...)
+				d[j] = c
+				if z, err := jscan.LoadLineIndex(testNDJSON, d); err == nil {
+					for n := 0; n < z.Len(); n++ {
+						_, _ = z.Record(n)
+						_ = z.Line(n)
+						_, _ = z.Key(n)
+					}
+					_ = z.Lookup("a")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+//go:build linux
+
+package jscan
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmap maps size bytes of f into memory read-only.
+func mmap(f *os.File, size int) ([]byte, error) {
+	b, err := syscall.Mmap(
+		int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED,
+	)
+	if err != nil {
+		return nil, err
+	}
+	// The mapping is read once from start to end.
+	_ = syscall.Madvise(b, syscall.MADV_SEQUENTIAL)
+	return b, nil
+}
+
+// munmap unmaps memory mapped by mmap.
+func munmap(b []byte) error { return syscall.Munmap(b) }
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package jscan
+
+import (
+	"errors"
+	"os"
+)
+
+// mmap always fails on platforms other than Linux.
+func mmap(*os.File, int) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// munmap is a no-op on platforms other than Linux.
+func munmap([]byte) error { return nil }
@@ -0,0 +1,142 @@
+package jscan
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/romshark/jscan/v2/internal/jsonnum"
+)
+
+var (
+	// ErrNotNumber is returned by the numeric accessors of Iterator
+	// when the current value isn't a number.
+	ErrNotNumber = errors.New("value is not a number")
+
+	// ErrNumberNotInteger is returned by the integer accessors of Iterator
+	// when the number has a fraction or an exponent part.
+	ErrNumberNotInteger = errors.New("number is not an integer")
+
+	// ErrNumberOverflow is returned by the numeric accessors of Iterator
+	// when the number is out of range of the requested type.
+	ErrNumberOverflow = errors.New("number out of range")
+)
+
+// Int64 returns the number value as int64.
+// Returns ErrNotNumber if the value isn't a number, ErrNumberNotInteger
+// if the number has a fraction or an exponent part (even if it's integral,
+// such as 1.0 or 1e3) and ErrNumberOverflow if it doesn't fit into int64.
+func (i *Iterator[S]) Int64() (int64, error) {
+	neg, n, err := i.parseInteger()
+	if err != nil {
+		return 0, err
+	}
+	if neg {
+		if n > 1<<63 {
+			return 0, ErrNumberOverflow
+		}
+		return -int64(n), nil
+	}
+	if n > math.MaxInt64 {
+		return 0, ErrNumberOverflow
+	}
+	return int64(n), nil
+}
+
+// Uint64 returns the number value as uint64.
+// Returns ErrNumberOverflow if the number is negative or doesn't fit into
+// uint64. See Int64 for more details.
+func (i *Iterator[S]) Uint64() (uint64, error) {
+	neg, n, err := i.parseInteger()
+	if err != nil {
+		return 0, err
+	}
+	if neg && n != 0 {
+		return 0, ErrNumberOverflow
+	}
+	return n, nil
+}
+
+// Int returns the number value as int.
+// Returns ErrNumberOverflow if the number doesn't fit into int.
+// See Int64 for more details.
+func (i *Iterator[S]) Int() (int, error) {
+	n, err := i.Int64()
+	if err != nil {
+		return 0, err
+	}
+	if n < math.MinInt || n > math.MaxInt {
+		return 0, ErrNumberOverflow
+	}
+	return int(n), nil
+}
+
+// Float64 returns the number value as float64 rounded to the nearest
+// representable value. Returns ErrNotNumber if the value isn't a number and
+// ErrNumberOverflow together with ±Inf if the number is out of range of
+// float64. Numbers too small to be represented are rounded to zero.
+func (i *Iterator[S]) Float64() (float64, error) {
+	if i.valueType != ValueTypeNumber {
+		return 0, ErrNotNumber
+	}
+	v := i.src[i.valueIndex:i.valueIndexEnd]
+
+	// Integers of up to 15 digits are represented exactly.
+	if d := len(v); i.numberKind == jsonnum.ReturnCodeInteger &&
+		(d <= 15 || (v[0] == '-' && d <= 16)) {
+		return exactFloat64(v), nil
+	}
+
+	f, err := strconv.ParseFloat(unsafeString(v), 64)
+	if err != nil {
+		// The value is known to be a valid number,
+		// hence the only possible error is ErrRange.
+		return f, ErrNumberOverflow
+	}
+	return f, nil
+}
+
+// parseInteger parses the current number value as an integer and returns
+// its sign and absolute value.
+func (i *Iterator[S]) parseInteger() (neg bool, n uint64, err error) {
+	if i.valueType != ValueTypeNumber {
+		return false, 0, ErrNotNumber
+	}
+	if i.numberKind != jsonnum.ReturnCodeInteger {
+		return false, 0, ErrNumberNotInteger
+	}
+	return parseInteger(i.src[i.valueIndex:i.valueIndexEnd])
+}
+
+// parseInteger parses the JSON number v in integer notation
+// and returns its sign and absolute value.
+func parseInteger[S ~string | ~[]byte](v S) (neg bool, n uint64, err error) {
+	if v[0] == '-' {
+		neg, v = true, v[1:]
+	}
+	for j := 0; j < len(v); j++ {
+		d := uint64(v[j] - '0')
+		if n > (math.MaxUint64-d)/10 {
+			return false, 0, ErrNumberOverflow
+		}
+		n = n*10 + d
+	}
+	return neg, n, nil
+}
+
+// exactFloat64 converts the valid JSON integer v of no more than
+// 15 digits to float64.
+func exactFloat64[S ~string | ~[]byte](v S) float64 {
+	neg := v[0] == '-'
+	if neg {
+		v = v[1:]
+	}
+	var n int64
+	for j := 0; j < len(v); j++ {
+		n = n*10 + int64(v[j]-'0')
+	}
+	if neg {
+		return -float64(n)
+	}
+	return float64(n)
+}
@@ -0,0 +1,176 @@
+package jscan_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type NumberRecord struct {
+	Int64      int64
+	Int64Err   error
+	Uint64     uint64
+	Uint64Err  error
+	Int        int
+	IntErr     error
+	Float64    float64
+	Float64Err error
+}
+
+// numberExpect is the expected NumberRecord except for Int and IntErr, which
+// are derived from Int64 and Int64Err since the size of int is platform
+// dependent.
+type numberExpect struct {
+	Int64      int64
+	Int64Err   error
+	Uint64     uint64
+	Uint64Err  error
+	Float64    float64
+	Float64Err error
+}
+
+func TestNumberAccessors(t *testing.T) {
+	var (
+		errNaN = jscan.ErrNotNumber
+		errInt = jscan.ErrNumberNotInteger
+		errOvf = jscan.ErrNumberOverflow
+	)
+	for _, td := range []struct {
+		input  string
+		expect numberExpect
+	}{
+		{`0`, numberExpect{0, nil, 0, nil, 0, nil}},
+		{`-0`, numberExpect{0, nil, 0, nil, math.Copysign(0, -1), nil}},
+		{`42`, numberExpect{42, nil, 42, nil, 42, nil}},
+		{`-42`, numberExpect{-42, nil, 0, errOvf, -42, nil}},
+		{`999999999999999`, numberExpect{
+			999999999999999, nil, 999999999999999, nil, 999999999999999, nil,
+		}},
+		{`9223372036854775807`, numberExpect{
+			math.MaxInt64, nil, math.MaxInt64, nil, 9223372036854775807, nil,
+		}},
+		{`9223372036854775808`, numberExpect{
+			0, errOvf, 1 << 63, nil, 9223372036854775808, nil,
+		}},
+		{`-9223372036854775808`, numberExpect{
+			math.MinInt64, nil, 0, errOvf, -9223372036854775808, nil,
+		}},
+		{`-9223372036854775809`, numberExpect{
+			0, errOvf, 0, errOvf, -9223372036854775809, nil,
+		}},
+		{`18446744073709551615`, numberExpect{
+			0, errOvf, math.MaxUint64, nil, 18446744073709551615, nil,
+		}},
+		{`18446744073709551616`, numberExpect{
+			0, errOvf, 0, errOvf, 18446744073709551616, nil,
+		}},
+		{`1.0`, numberExpect{0, errInt, 0, errInt, 1, nil}},
+		{`1e3`, numberExpect{0, errInt, 0, errInt, 1000, nil}},
+		{`-1.5E-3`, numberExpect{0, errInt, 0, errInt, -0.0015, nil}},
+		{`1e400`, numberExpect{0, errInt, 0, errInt, math.Inf(1), errOvf}},
+		{`-1e400`, numberExpect{0, errInt, 0, errInt, math.Inf(-1), errOvf}},
+		{`1e-400`, numberExpect{0, errInt, 0, errInt, 0, nil}},
+		{`"1"`, numberExpect{0, errNaN, 0, errNaN, 0, errNaN}},
+		{`null`, numberExpect{0, errNaN, 0, errNaN, 0, errNaN}},
+		{`[]`, numberExpect{0, errNaN, 0, errNaN, 0, errNaN}},
+	} {
+		t.Run(td.input, func(t *testing.T) {
+			testNumberAccessors(t, td.input, td.expect)
+			testNumberAccessors(t, []byte(td.input), td.expect)
+		})
+	}
+}
+
+func testNumberAccessors[S ~string | ~[]byte](
+	t *testing.T, input S, e numberExpect,
+) {
+	expect := NumberRecord{
+		Int64: e.Int64, Int64Err: e.Int64Err,
+		Uint64: e.Uint64, Uint64Err: e.Uint64Err,
+		Int: int(e.Int64), IntErr: e.Int64Err,
+		Float64: e.Float64, Float64Err: e.Float64Err,
+	}
+	if e.Int64 < math.MinInt || e.Int64 > math.MaxInt {
+		expect.Int, expect.IntErr = 0, jscan.ErrNumberOverflow
+	}
+	var actual []NumberRecord
+	err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+		var r NumberRecord
+		r.Int64, r.Int64Err = i.Int64()
+		r.Uint64, r.Uint64Err = i.Uint64()
+		r.Int, r.IntErr = i.Int()
+		r.Float64, r.Float64Err = i.Float64()
+		actual = append(actual, r)
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+	require.Equal(t, []NumberRecord{expect}, actual)
+	// require.Equal doesn't distinguish between 0 and -0.
+	require.Equal(t,
+		math.Signbit(expect.Float64), math.Signbit(actual[0].Float64))
+}
+
+func TestFloat64(t *testing.T) {
+	for _, input := range []string{
+		`0`, `1`, `-1`, `-0.0`, `1E+2`,
+		`123456789012345`, `-123456789012345`, `1234567890123456`,
+		`12345678901234567890`, `9007199254740993`, `0.1`,
+		`0.30000000000000004`, `1.7976931348623157e308`, `4.9e-324`,
+		`2.2250738585072014E-308`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			expect, err := strconv.ParseFloat(input, 64)
+			require.NoError(t, err)
+			testFloat64(t, input, expect)
+			testFloat64(t, []byte(input), expect)
+		})
+	}
+}
+
+func testFloat64[S ~string | ~[]byte](t *testing.T, input S, expect float64) {
+	err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+		f, err2 := i.Float64()
+		require.NoError(t, err2)
+		require.Equal(t, math.Float64bits(expect), math.Float64bits(f))
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+}
+
+func TestNumberKind(t *testing.T) {
+	const input = `[0,-0,42,-7,1.0,1e3,-1.5E-3,0.1,"1",null,{}]`
+	I, F := jscan.NumberKindInteger, jscan.NumberKindFloat
+	expect := []jscan.NumberKind{0, I, I, I, I, F, F, F, F, 0, 0, 0}
+
+	testNumberKind(t, string(input), expect)
+	testNumberKind(t, []byte(input), expect)
+
+	require.Equal(t, "integer", I.String())
+	require.Equal(t, "float", F.String())
+	require.Equal(t, "", jscan.NumberKind(0).String())
+}
+
+func testNumberKind[S ~string | ~[]byte](
+	t *testing.T, input S, expect []jscan.NumberKind,
+) {
+	t.Run(testDataType(input), func(t *testing.T) {
+		var actual []jscan.NumberKind
+		err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+			actual = append(actual, i.NumberKind())
+			return false
+		})
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+		require.Equal(t, expect, actual)
+
+		actual = nil
+		r := jscan.NewReader(input)
+		for r.Next() {
+			actual = append(actual, r.NumberKind())
+		}
+		require.False(t, r.Err().IsErr(), "unexpected error: %s", r.Err())
+		require.Equal(t, expect, actual)
+	})
+}
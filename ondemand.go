@@ -0,0 +1,389 @@
+package jscan
+
+import (
+	"errors"
+
+	"github.com/romshark/jscan/v2/internal/strfind"
+	"github.com/romshark/jscan/v2/internal/unescape"
+)
+
+var (
+	// ErrNotObject is returned by (Value).Field and (Value).EachField
+	// when the value isn't an object.
+	ErrNotObject = errors.New("value is not an object")
+
+	// ErrNotArray is returned by (Value).Each when the value isn't an array.
+	ErrNotArray = errors.New("value is not an array")
+
+	// ErrNotBool is returned by (Value).Bool when the value isn't a boolean.
+	ErrNotBool = errors.New("value is not a boolean")
+
+	// ErrFieldNotFound is returned by the accessors of a Value
+	// obtained from (Value).Field when the object has no member with
+	// the given key following the last accessed member.
+	ErrFieldNotFound = errors.New("field not found")
+
+	// ErrOutOfOrder is returned by the accessors of a Value
+	// when the cursor of its Document has already moved past it.
+	ErrOutOfOrder = errors.New("value accessed out of document order")
+)
+
+// Document is a forward-only on-demand cursor over a JSON document
+// created by OnDemand. Values are only parsed when accessed and
+// untouched values are skipped by the validator.
+//
+// Values must be accessed in document order: once the cursor moved past
+// a value, such as by accessing a member following it, the value can no
+// longer be accessed and its accessors return ErrOutOfOrder.
+// Syntax errors are returned as Error[S] and are sticky, all subsequent
+// accessors return the same error.
+type Document[S ~string | ~[]byte] struct {
+	Value[S]
+}
+
+// Value is a value of a Document. The zero value is invalid.
+type Value[S ~string | ~[]byte] struct {
+	d     *onDemand[S]
+	err   error
+	depth int
+	start int
+}
+
+// onDemand is the cursor state of a Document.
+// The cursor is the current value or end event of r.
+type onDemand[S ~string | ~[]byte] struct {
+	r Reader[S]
+
+	// started is true once the reader was advanced to the first value.
+	started bool
+
+	// pending is true when the cursor is at a value that wasn't passed yet.
+	pending bool
+
+	// entered is true when the cursor is at an object or array the members
+	// or elements of which are read next.
+	entered bool
+
+	err error
+}
+
+// OnDemand creates a new forward-only cursor over s.
+// No part of s is read until it's accessed.
+// Use (*Document).End to validate the remainder of s.
+//
+//	doc := jscan.OnDemand(src)
+//	id, err := doc.Field("user").Field("id").Int64()
+func OnDemand[S ~string | ~[]byte](s S) *Document[S] {
+	d := new(onDemand[S])
+	d.r.Iterator = Iterator[S]{
+		stack:     make([]stackNode, 0, DefaultStackSizeIterator),
+		skipStack: make([]stackNodeType, 0, DefaultStackSizeValidator),
+		endEvents: true,
+		pull:      true,
+	}
+	d.r.Reset(s)
+	t, _ := strfind.EndOfWhitespaceSeq(s)
+	return &Document[S]{Value: Value[S]{d: d, start: len(s) - len(t)}}
+}
+
+// End moves the cursor to the end of the document validating
+// all values that weren't read yet and returns an error if the document
+// is invalid or if it's followed by anything other than whitespace.
+func (doc *Document[S]) End() error {
+	d := doc.d
+	if err := d.start(); err != nil {
+		return err
+	}
+	for {
+		d.skipPending()
+		if !d.r.Next() {
+			break
+		}
+		d.pending = !d.r.end
+	}
+	if err := d.r.Err(); err.IsErr() {
+		return d.fail(err)
+	}
+	return nil
+}
+
+// Err returns the error of v if any, such as ErrFieldNotFound.
+// Syntax errors are only reported once the invalid part of the document
+// is read.
+func (v Value[S]) Err() error { return v.err }
+
+// Type returns the type of v without reading it.
+// Returns 0 if v is invalid.
+func (v Value[S]) Type() ValueType {
+	if v.err != nil || v.start >= len(v.d.r.src) {
+		return 0
+	}
+	switch v.d.r.src[v.start] {
+	case '{':
+		return ValueTypeObject
+	case '[':
+		return ValueTypeArray
+	case '"':
+		return ValueTypeString
+	case 'n':
+		return ValueTypeNull
+	case 'f':
+		return ValueTypeFalse
+	case 't':
+		return ValueTypeTrue
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return ValueTypeNumber
+	}
+	return 0
+}
+
+// Field returns the value of the object member with the unescaped key name.
+// Members preceding it are skipped. Since the cursor only moves forward,
+// only members following the last accessed member can be found.
+// If there is no such member the returned value reports ErrFieldNotFound.
+func (v Value[S]) Field(name string) Value[S] {
+	d := v.d
+	if err := d.resume(v, ValueTypeObject, ErrNotObject); err != nil {
+		return Value[S]{d: d, err: err}
+	}
+	for {
+		more, err := d.next(v)
+		if err != nil {
+			return Value[S]{d: d, err: err}
+		}
+		if !more {
+			return Value[S]{d: d, err: ErrFieldNotFound}
+		}
+		if d.r.KeyEquals(name) {
+			return Value[S]{d: d, depth: v.depth + 1, start: d.r.valueIndex}
+		}
+	}
+}
+
+// Each calls fn for every remaining element of the array.
+// Elements fn doesn't access are skipped.
+// Returns an error of code ErrorCodeCallback if fn returns true.
+func (v Value[S]) Each(fn func(Value[S]) (err bool)) error {
+	d := v.d
+	for {
+		if err := d.resume(v, ValueTypeArray, ErrNotArray); err != nil {
+			return err
+		}
+		more, err := d.next(v)
+		if err != nil || !more {
+			return err
+		}
+		e := Value[S]{d: d, depth: v.depth + 1, start: d.r.valueIndex}
+		if fn(e) {
+			return Error[S]{Src: d.r.src, Index: e.start, Code: ErrorCodeCallback}
+		}
+	}
+}
+
+// EachField calls fn for every remaining member of the object
+// with its key as it appears in the source including the quotation marks.
+// Member values fn doesn't access are skipped.
+// Returns an error of code ErrorCodeCallback if fn returns true.
+func (v Value[S]) EachField(fn func(key S, value Value[S]) (err bool)) error {
+	d := v.d
+	for {
+		if err := d.resume(v, ValueTypeObject, ErrNotObject); err != nil {
+			return err
+		}
+		more, err := d.next(v)
+		if err != nil || !more {
+			return err
+		}
+		e := Value[S]{d: d, depth: v.depth + 1, start: d.r.valueIndex}
+		if fn(d.r.Key(), e) {
+			return Error[S]{Src: d.r.src, Index: e.start, Code: ErrorCodeCallback}
+		}
+	}
+}
+
+// Int64 reads the number value as int64, see (*Iterator).Int64.
+func (v Value[S]) Int64() (int64, error) {
+	i, err := v.d.read(v)
+	if err != nil {
+		return 0, err
+	}
+	return i.Int64()
+}
+
+// Uint64 reads the number value as uint64, see (*Iterator).Uint64.
+func (v Value[S]) Uint64() (uint64, error) {
+	i, err := v.d.read(v)
+	if err != nil {
+		return 0, err
+	}
+	return i.Uint64()
+}
+
+// Float64 reads the number value as float64, see (*Iterator).Float64.
+func (v Value[S]) Float64() (float64, error) {
+	i, err := v.d.read(v)
+	if err != nil {
+		return 0, err
+	}
+	return i.Float64()
+}
+
+// Decimal reads the exact decimal value of the number value,
+// see (*Iterator).Decimal.
+func (v Value[S]) Decimal() (Decimal, error) {
+	i, err := v.d.read(v)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return i.Decimal()
+}
+
+// Bool reads the boolean value.
+// Returns ErrNotBool if the value isn't a boolean.
+func (v Value[S]) Bool() (bool, error) {
+	i, err := v.d.read(v)
+	if err != nil {
+		return false, err
+	}
+	switch i.valueType {
+	case ValueTypeTrue:
+		return true, nil
+	case ValueTypeFalse:
+		return false, nil
+	}
+	return false, ErrNotBool
+}
+
+// IsNull reads the value and returns true if it's null.
+func (v Value[S]) IsNull() (bool, error) {
+	i, err := v.d.read(v)
+	if err != nil {
+		return false, err
+	}
+	return i.valueType == ValueTypeNull, nil
+}
+
+// Text reads the unescaped string value.
+// Returns ErrNotString if the value isn't a string.
+func (v Value[S]) Text() (string, error) {
+	i, err := v.d.read(v)
+	if err != nil {
+		return "", err
+	}
+	if i.valueType != ValueTypeString {
+		return "", ErrNotString
+	}
+	if i.valueEscaped {
+		return string(unescape.Append(nil, i.stringContent())), nil
+	}
+	return string(i.stringContent()), nil
+}
+
+// Raw reads the entire value as it appears in the source.
+// Objects and arrays are validated but not parsed.
+func (v Value[S]) Raw() (S, error) {
+	i, err := v.d.read(v)
+	if err != nil {
+		var zero S
+		return zero, err
+	}
+	return i.Value(), nil
+}
+
+// fail makes err sticky and returns it.
+func (d *onDemand[S]) fail(err Error[S]) error {
+	d.err = err
+	return err
+}
+
+// start advances the reader to the first value unless it was already.
+// Returns the sticky error if any.
+func (d *onDemand[S]) start() error {
+	if d.err != nil || d.started {
+		return d.err
+	}
+	d.started = true
+	if !d.r.Next() {
+		return d.fail(d.r.Err())
+	}
+	d.pending = true
+	return nil
+}
+
+// is returns true if the cursor is at v or at the end event of v.
+func (d *onDemand[S]) is(v Value[S]) bool {
+	return d.r.valueIndex == v.start && len(d.r.stack) == v.depth
+}
+
+// resume prepares reading the members or elements of the object or
+// array v entering v if the cursor is at it.
+func (d *onDemand[S]) resume(v Value[S], t ValueType, errType error) error {
+	if v.err != nil {
+		return v.err
+	}
+	if err := d.start(); err != nil {
+		return err
+	}
+	if v.Type() != t {
+		return errType
+	}
+	switch {
+	case d.is(v) && d.pending:
+		d.pending, d.entered = false, true
+	case d.is(v) && (d.entered || d.r.end):
+	case len(d.r.stack) <= v.depth || d.r.stack[v.depth].Index != v.start:
+		return ErrOutOfOrder
+	}
+	return nil
+}
+
+// next moves the cursor to the next member or element of the object
+// or array v previously prepared by resume and returns true.
+// Returns false if the end of v was reached instead.
+func (d *onDemand[S]) next(v Value[S]) (more bool, err error) {
+	for {
+		if d.r.end && d.is(v) {
+			return false, nil
+		}
+		d.skipPending()
+		if !d.r.Next() {
+			return false, d.fail(d.r.Err())
+		}
+		d.pending = !d.r.end
+		if d.pending && len(d.r.stack) == v.depth+1 {
+			return true, nil
+		}
+	}
+}
+
+// skipPending makes the reader skip the members or elements of
+// the object or array at the cursor unless it was entered.
+func (d *onDemand[S]) skipPending() {
+	if d.pending {
+		d.r.Skip()
+	}
+	d.pending, d.entered = false, false
+}
+
+// read reads the value v at the cursor and returns the iterator
+// providing access to it. Objects and arrays are validated and skipped.
+func (d *onDemand[S]) read(v Value[S]) (*Iterator[S], error) {
+	if v.err != nil {
+		return nil, v.err
+	}
+	if err := d.start(); err != nil {
+		return nil, err
+	}
+	if !d.is(v) || d.entered {
+		return nil, ErrOutOfOrder
+	}
+	if d.pending && (d.r.valueType == ValueTypeObject ||
+		d.r.valueType == ValueTypeArray) {
+		// Read the end event of v.
+		d.skipPending()
+		if !d.r.Next() {
+			return nil, d.fail(d.r.Err())
+		}
+	}
+	return &d.r.Iterator, nil
+}
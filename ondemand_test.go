@@ -0,0 +1,273 @@
+package jscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnDemand(t *testing.T) {
+	const input = ` {
+		"skip": {"x": [1, 2, {"y": null}], "z": "\"}"},
+		"user": {"id": 42, "name": "Jörg", "admin": true, "extra": [1]},
+		"items": [{"v": 1.5}, {"v": -2}, {"w": 0}, "str"],
+		"empty": {},
+		"tail": null
+	} `
+
+	t.Run("string", func(t *testing.T) { testOnDemand(t, string(input)) })
+	t.Run("bytes", func(t *testing.T) { testOnDemand(t, []byte(input)) })
+}
+
+func testOnDemand[S ~string | ~[]byte](t *testing.T, input S) {
+	doc := jscan.OnDemand(input)
+	require.Equal(t, jscan.ValueTypeObject, doc.Type())
+
+	user := doc.Field("user")
+	require.NoError(t, user.Err())
+	require.Equal(t, jscan.ValueTypeObject, user.Type())
+
+	id, err := user.Field("id").Int64()
+	require.NoError(t, err)
+	require.Equal(t, int64(42), id)
+
+	name := user.Field("name")
+	text, err := name.Text()
+	require.NoError(t, err)
+	require.Equal(t, "Jörg", text)
+	raw, err := name.Raw() // Repeated reads of the last value are allowed.
+	require.NoError(t, err)
+	require.Equal(t, `"Jörg"`, string(raw))
+
+	admin, err := user.Field("admin").Bool()
+	require.NoError(t, err)
+	require.True(t, admin)
+
+	// "id" was passed already.
+	_, err = user.Field("id").Int64()
+	require.ErrorIs(t, err, jscan.ErrFieldNotFound)
+
+	var values []float64
+	var kinds []jscan.ValueType
+	err = doc.Field("items").Each(func(v jscan.Value[S]) (err bool) {
+		kinds = append(kinds, v.Type())
+		if v.Type() != jscan.ValueTypeObject {
+			return false
+		}
+		if f, err := v.Field("v").Float64(); err == nil {
+			values = append(values, f)
+		}
+		return false
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float64{1.5, -2}, values)
+	require.Equal(t, []jscan.ValueType{
+		jscan.ValueTypeObject, jscan.ValueTypeObject,
+		jscan.ValueTypeObject, jscan.ValueTypeString,
+	}, kinds)
+
+	// The cursor moved past "user".
+	_, err = user.Field("extra").Raw()
+	require.ErrorIs(t, err, jscan.ErrOutOfOrder)
+
+	var keys []string
+	require.NoError(t, doc.Field("empty").EachField(
+		func(key S, _ jscan.Value[S]) (err bool) {
+			keys = append(keys, string(key))
+			return false
+		},
+	))
+	require.Len(t, keys, 0)
+
+	isNull, err := doc.Field("tail").IsNull()
+	require.NoError(t, err)
+	require.True(t, isNull)
+
+	require.NoError(t, doc.End())
+}
+
+func TestOnDemandEachField(t *testing.T) {
+	doc := jscan.OnDemand(`{"a": {"b": 1}, "c\/": [2], "d": 3}`)
+	var keys []string
+	var raw []string
+	err := doc.EachField(func(key string, v jscan.Value[string]) (err bool) {
+		keys = append(keys, key)
+		if key == `"d"` {
+			r, err := v.Raw()
+			require.NoError(t, err)
+			raw = append(raw, r)
+		}
+		return false
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{`"a"`, `"c\/"`, `"d"`}, keys)
+	require.Equal(t, []string{`3`}, raw)
+	require.NoError(t, doc.End())
+}
+
+func TestOnDemandErr(t *testing.T) {
+	for _, td := range []struct {
+		name   string
+		input  string
+		access func(*jscan.Document[string]) error
+		expect error
+	}{
+		{
+			name:  "not_object",
+			input: `[1]`,
+			access: func(d *jscan.Document[string]) error {
+				return d.Field("a").Err()
+			},
+			expect: jscan.ErrNotObject,
+		},
+		{
+			name:  "not_array",
+			input: `{"a":1}`,
+			access: func(d *jscan.Document[string]) error {
+				return d.Each(func(jscan.Value[string]) bool { return false })
+			},
+			expect: jscan.ErrNotArray,
+		},
+		{
+			name:  "not_bool",
+			input: `{"a":1}`,
+			access: func(d *jscan.Document[string]) error {
+				_, err := d.Field("a").Bool()
+				return err
+			},
+			expect: jscan.ErrNotBool,
+		},
+		{
+			name:  "not_number",
+			input: `{"a":"1"}`,
+			access: func(d *jscan.Document[string]) error {
+				_, err := d.Field("a").Int64()
+				return err
+			},
+			expect: jscan.ErrNotNumber,
+		},
+		{
+			name:  "not_string",
+			input: `{"a":1}`,
+			access: func(d *jscan.Document[string]) error {
+				_, err := d.Field("a").Text()
+				return err
+			},
+			expect: jscan.ErrNotString,
+		},
+		{
+			name:  "field_not_found_chained",
+			input: `{"a":1}`,
+			access: func(d *jscan.Document[string]) error {
+				_, err := d.Field("b").Field("c").Int64()
+				return err
+			},
+			expect: jscan.ErrFieldNotFound,
+		},
+	} {
+		t.Run(td.name, func(t *testing.T) {
+			require.ErrorIs(t, td.access(jscan.OnDemand(td.input)), td.expect)
+		})
+	}
+}
+
+func TestOnDemandSyntaxErr(t *testing.T) {
+	for _, td := range []struct {
+		name   string
+		input  string
+		access func(*jscan.Document[string]) error
+		expect jscan.Error[string]
+	}{
+		{
+			name:  "empty",
+			input: ` `,
+			access: func(d *jscan.Document[string]) error {
+				return d.Field("a").Err()
+			},
+			expect: jscan.Error[string]{Index: 1, Code: jscan.ErrorCodeUnexpectedEOF},
+		},
+		{
+			name:  "skipped_value",
+			input: `{"a":[1,2,x],"b":1}`,
+			access: func(d *jscan.Document[string]) error {
+				_, err := d.Field("b").Int64()
+				return err
+			},
+			expect: jscan.Error[string]{Index: 10, Code: jscan.ErrorCodeUnexpectedToken},
+		},
+		{
+			name:  "missing_colon",
+			input: `{"a" 1}`,
+			access: func(d *jscan.Document[string]) error {
+				return d.Field("a").Err()
+			},
+			expect: jscan.Error[string]{Index: 5, Code: jscan.ErrorCodeUnexpectedToken},
+		},
+		{
+			name:  "trailing_comma",
+			input: `{"a":1,}`,
+			access: func(d *jscan.Document[string]) error {
+				return d.Field("b").Err()
+			},
+			expect: jscan.Error[string]{Index: 7, Code: jscan.ErrorCodeUnexpectedToken},
+		},
+		{
+			name:  "malformed_number",
+			input: `[-]`,
+			access: func(d *jscan.Document[string]) error {
+				var err error
+				if e := d.Each(func(v jscan.Value[string]) bool {
+					_, err = v.Int64()
+					return err != nil
+				}); err == nil {
+					return e
+				}
+				return err
+			},
+			expect: jscan.Error[string]{Index: 1, Code: jscan.ErrorCodeMalformedNumber},
+		},
+		{
+			name:  "unterminated",
+			input: `{"a":1,"b":[2`,
+			access: func(d *jscan.Document[string]) error {
+				return d.End()
+			},
+			expect: jscan.Error[string]{Index: 13, Code: jscan.ErrorCodeUnexpectedEOF},
+		},
+		{
+			name:  "trailing",
+			input: `{"a":1} 2`,
+			access: func(d *jscan.Document[string]) error {
+				_, err := d.Field("a").Int64()
+				require.NoError(t, err)
+				return d.End()
+			},
+			expect: jscan.Error[string]{Index: 8, Code: jscan.ErrorCodeUnexpectedToken},
+		},
+	} {
+		t.Run(td.name, func(t *testing.T) {
+			d := jscan.OnDemand(td.input)
+			err := td.access(d)
+			var e jscan.Error[string]
+			require.True(t, errors.As(err, &e), "unexpected error: %v", err)
+			td.expect.Src = td.input
+			require.Equal(t, td.expect, e)
+			// Syntax errors are sticky.
+			require.Equal(t, err, d.End())
+		})
+	}
+}
+
+func TestOnDemandEachCallbackErr(t *testing.T) {
+	d := jscan.OnDemand(`[1, 2, 3]`)
+	err := d.Each(func(v jscan.Value[string]) (err bool) {
+		n, _ := v.Int64()
+		return n == 2
+	})
+	require.Equal(t, jscan.Error[string]{
+		Src: `[1, 2, 3]`, Index: 4, Code: jscan.ErrorCodeCallback,
+	}, err)
+	require.NoError(t, d.End())
+}
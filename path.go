@@ -0,0 +1,180 @@
+package jscan
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/romshark/jscan/v2/internal/unescape"
+)
+
+// NormalizedPath returns the RFC 9535 JSONPath normalized path
+// of the current value, such as `$['a'][0]`.
+func (i *Iterator[S]) NormalizedPath() (s S) {
+	i.ViewNormalizedPath(func(p []byte) { s = copyToS[S](p) })
+	return
+}
+
+// ViewNormalizedPath calls fn and provides the buffer holding the
+// RFC 9535 JSONPath normalized path of the current value.
+// Consider using (*Iterator[S]).NormalizedPath() instead
+// for safety and convenience.
+//
+// WARNING: do not use or alias p after fn returns,
+// only reading and copying p are considered safe!
+func (i *Iterator[S]) ViewNormalizedPath(fn func(p []byte)) {
+	i.path = i.AppendNormalizedPath(i.path[:0])
+	fn(i.path)
+}
+
+// AppendNormalizedPath appends the RFC 9535 JSONPath normalized path
+// of the current value to dst and returns the extended buffer.
+func (i *Iterator[S]) AppendNormalizedPath(dst []byte) []byte {
+	dst = append(dst, '$')
+	i.scanStack(func(key S, arrayIndex int) {
+		if arrayIndex == -1 {
+			dst = appendNormalizedPathName(dst, key[1:len(key)-1])
+			return
+		}
+		dst = append(dst, '[')
+		dst = strconv.AppendInt(dst, int64(arrayIndex), 10)
+		dst = append(dst, ']')
+	})
+	if i.keyIndex != -1 {
+		dst = appendNormalizedPathName(dst, i.src[i.keyIndex+1:i.keyIndexEnd-1])
+	}
+	return dst
+}
+
+// DotPath returns the path of the current value in dot/bracket notation
+// intended for human-facing diagnostics, such as `a.b[3]["weird key"]`.
+// Member keys that are valid identifiers are appended in dot notation,
+// all other keys are appended in bracket notation as they appear
+// in the source. The path of the root value is an empty string.
+func (i *Iterator[S]) DotPath() (s S) {
+	i.ViewDotPath(func(p []byte) { s = copyToS[S](p) })
+	return
+}
+
+// ViewDotPath calls fn and provides the buffer holding the path
+// of the current value in dot/bracket notation (see DotPath).
+// Consider using (*Iterator[S]).DotPath() instead for safety and convenience.
+//
+// WARNING: do not use or alias p after fn returns,
+// only reading and copying p are considered safe!
+func (i *Iterator[S]) ViewDotPath(fn func(p []byte)) {
+	i.path = i.AppendDotPath(i.path[:0])
+	fn(i.path)
+}
+
+// AppendDotPath appends the path of the current value in dot/bracket
+// notation (see DotPath) to dst and returns the extended buffer.
+func (i *Iterator[S]) AppendDotPath(dst []byte) []byte {
+	start := len(dst)
+	i.scanStack(func(key S, arrayIndex int) {
+		if arrayIndex == -1 {
+			dst = appendDotPathKey(dst, key, len(dst) == start)
+			return
+		}
+		dst = append(dst, '[')
+		dst = strconv.AppendInt(dst, int64(arrayIndex), 10)
+		dst = append(dst, ']')
+	})
+	if i.keyIndex != -1 {
+		dst = appendDotPathKey(
+			dst, i.src[i.keyIndex:i.keyIndexEnd], len(dst) == start,
+		)
+	}
+	return dst
+}
+
+// copyToS converts p to S copying it.
+func copyToS[S ~string | ~[]byte](p []byte) (s S) {
+	switch any(s).(type) {
+	case string:
+		return S(p)
+	}
+	b := make([]byte, len(p))
+	copy(b, p)
+	return S(b)
+}
+
+// appendNormalizedPathName appends the name selector for the member key
+// to dst. key is the contents of the key string in the source without
+// the surrounding quotation marks.
+func appendNormalizedPathName[S ~string | ~[]byte](dst []byte, key S) []byte {
+	dst = append(dst, '[', '\'')
+	for len(key) > 0 {
+		switch key[0] {
+		case '\'':
+			dst = append(dst, '\\', '\'')
+			key = key[1:]
+			continue
+		case '\\':
+		default:
+			dst = append(dst, key[0])
+			key = key[1:]
+			continue
+		}
+		r, n := unescape.Rune(key)
+		key = key[n:]
+		switch r {
+		case '\b':
+			dst = append(dst, '\\', 'b')
+		case '\f':
+			dst = append(dst, '\\', 'f')
+		case '\n':
+			dst = append(dst, '\\', 'n')
+		case '\r':
+			dst = append(dst, '\\', 'r')
+		case '\t':
+			dst = append(dst, '\\', 't')
+		case '\'':
+			dst = append(dst, '\\', '\'')
+		case '\\':
+			dst = append(dst, '\\', '\\')
+		default:
+			if r < 0x20 {
+				const hex = "0123456789abcdef"
+				dst = append(dst, '\\', 'u', '0', '0', hex[r>>4], hex[r&0xF])
+				continue
+			}
+			dst = utf8.AppendRune(dst, r)
+		}
+	}
+	return append(dst, '\'', ']')
+}
+
+// appendDotPathKey appends the member key to dst in dot notation if
+// it's an identifier, otherwise appends it in bracket notation.
+// key is the key string as it appears in the source including the
+// surrounding quotation marks.
+func appendDotPathKey[S ~string | ~[]byte](dst []byte, key S, first bool) []byte {
+	if !isIdentifier(key[1 : len(key)-1]) {
+		dst = append(dst, '[')
+		dst = append(dst, key...)
+		return append(dst, ']')
+	}
+	if !first {
+		dst = append(dst, '.')
+	}
+	return append(dst, key[1:len(key)-1]...)
+}
+
+// isIdentifier returns true if s is a non-empty sequence of ASCII letters,
+// digits and underscores that doesn't begin with a digit.
+func isIdentifier[S ~string | ~[]byte](s S) bool {
+	if len(s) < 1 || (s[0] >= '0' && s[0] <= '9') {
+		return false
+	}
+	for j := 0; j < len(s); j++ {
+		switch c := s[j]; {
+		case c >= 'a' && c <= 'z',
+			c >= 'A' && c <= 'Z',
+			c >= '0' && c <= '9',
+			c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
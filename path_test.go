@@ -0,0 +1,64 @@
+package jscan_test
+
+import (
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type PathRecord struct {
+	NormalizedPath string
+	DotPath        string
+}
+
+func TestPaths(t *testing.T) {
+	const input = `{"a":[0,{"b c":null}],"it's":{"BSLASH\\":{"CTRL\u0001\n":{"\u00e4\ud83d\ude00":1}}},"_x1":[[true]]}`
+	testPaths(t, string(input))
+	testPaths(t, []byte(input))
+}
+
+func testPaths[S ~string | ~[]byte](t *testing.T, input S) {
+	t.Run(testDataType(input), func(t *testing.T) {
+		var records []PathRecord
+		err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+			r := PathRecord{
+				NormalizedPath: string(i.NormalizedPath()),
+				DotPath:        string(i.DotPath()),
+			}
+			i.ViewNormalizedPath(func(p []byte) {
+				require.Equal(t, r.NormalizedPath, string(p))
+			})
+			i.ViewDotPath(func(p []byte) {
+				require.Equal(t, r.DotPath, string(p))
+			})
+			require.Equal(t, "prefix:"+r.NormalizedPath,
+				string(i.AppendNormalizedPath([]byte("prefix:"))))
+			require.Equal(t, "prefix:"+r.DotPath,
+				string(i.AppendDotPath([]byte("prefix:"))))
+			records = append(records, r)
+			return false
+		})
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+		require.Equal(t, []PathRecord{
+			{`$`, ``},
+			{`$['a']`, `a`},
+			{`$['a'][0]`, `a[0]`},
+			{`$['a'][1]`, `a[1]`},
+			{`$['a'][1]['b c']`, `a[1]["b c"]`},
+			{`$['it\'s']`, `["it's"]`},
+			{`$['it\'s']['BSLASH\\']`, `["it's"]["BSLASH\\"]`},
+			{
+				`$['it\'s']['BSLASH\\']['CTRL\u0001\n']`,
+				`["it's"]["BSLASH\\"]["CTRL\u0001\n"]`,
+			},
+			{
+				`$['it\'s']['BSLASH\\']['CTRL\u0001\n']['ä😀']`,
+				`["it's"]["BSLASH\\"]["CTRL\u0001\n"]["\u00e4\ud83d\ude00"]`,
+			},
+			{`$['_x1']`, `_x1`},
+			{`$['_x1'][0]`, `_x1[0]`},
+			{`$['_x1'][0][0]`, `_x1[0][0]`},
+		}, records)
+	})
+}
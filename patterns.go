@@ -0,0 +1,392 @@
+package jscan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/romshark/jscan/v2/internal/unescape"
+)
+
+// Patterns is a compiled set of JSON pointer patterns.
+// A pattern is an RFC-6901 JSON pointer in which the reference tokens
+// "*" and "**" are wildcards. "*" matches any single member key or
+// array index while "**" matches any sequence of zero or more of them.
+// For example, "/items/*/price" matches "/items/0/price" and
+// "/items/foo/price", while "/meta/**" matches "/meta", "/meta/x"
+// and "/meta/x/0".
+//
+// Patterns is immutable and safe for concurrent use.
+type Patterns struct {
+	root     *patternNode
+	patterns []string
+}
+
+type patternNode struct {
+	literals []patternLiteral
+
+	// any is the node following a "*" reference token.
+	any *patternNode
+
+	// deep is the node representing a "**" reference token.
+	deep *patternNode
+
+	// loop is true if the node represents a "**" reference token
+	// and hence matches any number of reference tokens.
+	loop bool
+
+	// match is the index of the pattern ending at this node or -1.
+	match int
+}
+
+type patternLiteral struct {
+	next *patternNode
+
+	// name is the unescaped reference token.
+	name string
+
+	// index is the array index represented by name or -1 if name
+	// isn't a valid array index.
+	index int
+}
+
+// CompilePatterns compiles patterns into a reusable set.
+// Returns an error if any of the patterns is not a valid JSON pointer.
+func CompilePatterns(patterns ...string) (*Patterns, error) {
+	p := &Patterns{
+		root:     &patternNode{match: -1},
+		patterns: patterns,
+	}
+	for index, pattern := range patterns {
+		if pattern != "" && pattern[0] != '/' {
+			return nil, fmt.Errorf(
+				"pattern %q: must either be empty or begin with '/'", pattern,
+			)
+		}
+		n := p.root
+		for s := pattern; s != ""; {
+			s = s[1:]
+			token := s
+			if x := strings.IndexByte(s, '/'); x != -1 {
+				token, s = s[:x], s[x:]
+			} else {
+				s = ""
+			}
+			switch token {
+			case "*":
+				if n.any == nil {
+					n.any = &patternNode{match: -1}
+				}
+				n = n.any
+				continue
+			case "**":
+				if n.deep == nil {
+					n.deep = &patternNode{match: -1, loop: true}
+				}
+				n = n.deep
+				continue
+			}
+			name, err := unescapePatternToken(token)
+			if err != nil {
+				return nil, fmt.Errorf("pattern %q: %w", pattern, err)
+			}
+			n = n.literal(name)
+		}
+		if n.match == -1 {
+			n.match = index
+		}
+	}
+	return p, nil
+}
+
+// Len returns the number of patterns in the set.
+func (p *Patterns) Len() int { return len(p.patterns) }
+
+// Pattern returns the pattern at the given index.
+func (p *Patterns) Pattern(index int) string { return p.patterns[index] }
+
+// literal returns the node following the literal reference token name
+// creating it if necessary.
+func (n *patternNode) literal(name string) *patternNode {
+	for i := range n.literals {
+		if n.literals[i].name == name {
+			return n.literals[i].next
+		}
+	}
+	l := patternLiteral{
+		next:  &patternNode{match: -1},
+		name:  name,
+		index: parseArrayIndex(name),
+	}
+	n.literals = append(n.literals, l)
+	return l.next
+}
+
+// unescapePatternToken replaces "~1" with "/" and "~0" with "~" in token.
+func unescapePatternToken(token string) (string, error) {
+	if strings.IndexByte(token, '~') == -1 {
+		return token, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(token); i++ {
+		if token[i] != '~' {
+			b.WriteByte(token[i])
+			continue
+		}
+		if i+1 >= len(token) || (token[i+1] != '0' && token[i+1] != '1') {
+			return "", fmt.Errorf("invalid escape sequence at index %d", i)
+		}
+		if i++; token[i] == '0' {
+			b.WriteByte('~')
+		} else {
+			b.WriteByte('/')
+		}
+	}
+	return b.String(), nil
+}
+
+// parseArrayIndex returns the array index represented by s
+// or -1 if s isn't a valid RFC-6901 array index.
+func parseArrayIndex(s string) int {
+	if s == "" || len(s) > 18 || (s[0] == '0' && len(s) > 1) {
+		return -1
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return -1
+		}
+		n = n*10 + int(s[i]-'0')
+	}
+	return n
+}
+
+// addPatternNode adds n and all nodes reachable from n without
+// consuming a reference token to the set set[start:] unless already contained.
+func addPatternNode(
+	set []*patternNode, start int, n *patternNode,
+) []*patternNode {
+	for ; n != nil; n = n.deep {
+		for _, x := range set[start:] {
+			if x == n {
+				return set
+			}
+		}
+		set = append(set, n)
+	}
+	return set
+}
+
+// Match returns the index of the first pattern in p that matches the
+// JSON pointer of the current value or -1 if none of the patterns match.
+// Match doesn't build the JSON pointer. The state of the objects and arrays
+// on the stack is cached such that matching every value of a scan against
+// the same set of patterns takes constant time per value regardless of
+// the depth of the value.
+func (i *Iterator[S]) Match(p *Patterns) int {
+	m := -1
+	for _, n := range i.matchPatterns(&i.matchCache, p) {
+		if n.match != -1 && (m == -1 || n.match < m) {
+			m = n.match
+		}
+	}
+	return m
+}
+
+// patternCache caches the sets of pattern nodes reached by the JSON pointers
+// of the objects and arrays on the stack of an iterator.
+type patternCache struct {
+	// patterns is the set of patterns the cache is valid for.
+	patterns *Patterns
+	levels   []patternLevel
+	sets     []*patternNode
+}
+
+// patternLevel is the cached state of an object or array on the stack.
+type patternLevel struct {
+	// index is the start index of the object or array the state
+	// belongs to, which uniquely identifies it within a scan.
+	index int
+
+	// end is the end of its set of pattern nodes in sets.
+	end int
+}
+
+// matchPatterns returns the set of pattern nodes of p reached by the JSON
+// pointer of the current value using and updating the cache c.
+func (i *Iterator[S]) matchPatterns(c *patternCache, p *Patterns) []*patternNode {
+	if c.patterns != p {
+		c.patterns, c.levels = p, c.levels[:0]
+	}
+	// Find the deepest object or array on the stack the state of which
+	// is cached. If a state is cached, the state of all its ancestors is too.
+	valid := min(len(c.levels), len(i.stack))
+	for valid > 0 && c.levels[valid-1].index != i.stack[valid-1].Index {
+		valid--
+	}
+	c.levels = c.levels[:valid]
+	c.sets = c.sets[:c.setEnd(valid)]
+	for j := valid; j < len(i.stack); j++ {
+		if j == 0 {
+			c.sets = addPatternNode(c.sets, 0, p.root)
+		} else {
+			c.sets = i.stepPatterns(c.sets, c.setEnd(j-1), j-1, i.stackKey(j))
+		}
+		c.levels = append(c.levels, patternLevel{
+			index: i.stack[j].Index,
+			end:   len(c.sets),
+		})
+	}
+
+	// The set of the current value isn't cached.
+	end := len(c.sets)
+	if len(i.stack) == 0 {
+		c.sets = addPatternNode(c.sets, end, p.root)
+	} else {
+		c.sets = i.stepPatterns(c.sets, c.setEnd(len(i.stack)-1),
+			len(i.stack)-1, i.Key())
+	}
+	set := c.sets[end:]
+	c.sets = c.sets[:end]
+	return set
+}
+
+// setEnd returns the end of the cached set of pattern nodes
+// of the object or array at the given level in sets.
+func (c *patternCache) setEnd(level int) int {
+	if level < 1 {
+		return 0
+	}
+	return c.levels[level-1].end
+}
+
+// stepPatterns appends the set of pattern nodes reached from the set
+// sets[from:] by the reference token of the child of the object or array
+// at the given level to sets. key is the key of the child including
+// the quotes if the object or array is an object.
+func (i *Iterator[S]) stepPatterns(
+	sets []*patternNode, from, level int, key S,
+) []*patternNode {
+	index := -1
+	if i.stack[level].Type == stackNodeTypeArray {
+		index = i.stack[level].Len - 1
+	}
+	start := len(sets)
+	for j := from; j < start; j++ {
+		n := sets[j]
+		if n.loop {
+			sets = addPatternNode(sets, start, n)
+		}
+		if n.any != nil {
+			sets = addPatternNode(sets, start, n.any)
+		}
+		for k := range n.literals {
+			l := &n.literals[k]
+			if index != -1 {
+				if l.index == index {
+					sets = addPatternNode(sets, start, l.next)
+				}
+				continue
+			}
+			if unescape.Equal(key[1:len(key)-1], l.name) {
+				sets = addPatternNode(sets, start, l.next)
+			}
+		}
+	}
+	return sets
+}
+
+// Router dispatches values to handlers by JSON pointer patterns.
+// See Patterns for the pattern syntax.
+// Router is immutable and safe for concurrent use.
+type Router[S ~string | ~[]byte] struct {
+	p        *Patterns
+	handlers []func(*Iterator[S]) (err bool)
+}
+
+// NewRouter compiles routes into a reusable router.
+// Returns an error if any of the patterns is not a valid JSON pointer.
+func NewRouter[S ~string | ~[]byte](
+	routes map[string]func(*Iterator[S]) (err bool),
+) (*Router[S], error) {
+	patterns := make([]string, 0, len(routes))
+	for pattern := range routes {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	p, err := CompilePatterns(patterns...)
+	if err != nil {
+		return nil, err
+	}
+	r := &Router[S]{p: p, handlers: make([]func(*Iterator[S]) bool, len(patterns))}
+	for i, pattern := range patterns {
+		r.handlers[i] = routes[pattern]
+	}
+	return r, nil
+}
+
+// Scan calls the handlers of all patterns matching the JSON pointer of
+// every encountered value in the lexicographical order of the patterns.
+// Objects and arrays that can't contain any further matching values are
+// skipped without calling any handlers (see ActionSkipChildren).
+//
+// WARNING: Don't use or alias *Iterator[S] after the handler returns!
+func (r *Router[S]) Scan(s S) Error[S] {
+	return ScanAction(s, r.route)
+}
+
+// ScanParser is similar to (*Router).Scan but uses parser p.
+//
+// WARNING: Don't use or alias *Iterator[S] after the handler returns!
+func (r *Router[S]) ScanParser(p *Parser[S], s S) Error[S] {
+	return p.ScanAction(s, r.route)
+}
+
+func (r *Router[S]) route(i *Iterator[S]) Action {
+	var (
+		buf     [8]int
+		matches = buf[:0]
+		alive   bool
+	)
+	for _, n := range i.matchPatterns(&i.routeCache, r.p) {
+		if n.match != -1 {
+			matches = append(matches, n.match)
+		}
+		if n.loop || n.any != nil || len(n.literals) > 0 {
+			alive = true
+		}
+	}
+	if len(matches) > 1 {
+		sort.Ints(matches)
+	}
+	for _, m := range matches {
+		if r.handlers[m](i) {
+			return ActionStop
+		}
+	}
+	if !alive {
+		return ActionSkipChildren
+	}
+	return ActionContinue
+}
+
+// ScanRoutes is a shorthand for NewRouter(routes) followed by (*Router).Scan.
+// Consider reusing a Router instead to avoid compiling the patterns
+// on every call.
+// Returns the error returned by NewRouter if any of the patterns is
+// not a valid JSON pointer, otherwise returns the Error[S] returned by
+// (*Router).Scan or nil.
+//
+// WARNING: Don't use or alias *Iterator[S] after the handler returns!
+func ScanRoutes[S ~string | ~[]byte](
+	s S, routes map[string]func(*Iterator[S]) (err bool),
+) error {
+	r, err := NewRouter(routes)
+	if err != nil {
+		return err
+	}
+	if err := r.Scan(s); err.IsErr() {
+		return err
+	}
+	return nil
+}
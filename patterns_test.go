@@ -0,0 +1,201 @@
+package jscan_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilePatternsErr(t *testing.T) {
+	for _, td := range []struct {
+		pattern string
+		expect  string
+	}{
+		{"a", `pattern "a": must either be empty or begin with '/'`},
+		{"/a~", `pattern "/a~": invalid escape sequence at index 1`},
+		{"/a/~2", `pattern "/a/~2": invalid escape sequence at index 0`},
+	} {
+		t.Run(td.pattern, func(t *testing.T) {
+			p, err := jscan.CompilePatterns("/ok", td.pattern)
+			require.Nil(t, p)
+			require.EqualError(t, err, td.expect)
+
+			r, err := jscan.NewRouter(map[string]func(*jscan.Iterator[string]) bool{
+				td.pattern: func(*jscan.Iterator[string]) bool { return false },
+			})
+			require.Nil(t, r)
+			require.EqualError(t, err, td.expect)
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	const input = `{
+		"items": [
+			{"price": 1, "name": "a"},
+			{"price": 2, "tags": ["x", "y"]}
+		],
+		"meta": {"a/b": {"~": [null]}, "0": false},
+		"escaped": "v",
+		"price": 3
+	}`
+	patterns := []string{
+		"",
+		"/items/*/price",
+		"/meta/**",
+		"/items/1/tags/*",
+		"/**/price",
+		"/meta/a~1b/~0",
+		"/escaped",
+		"/meta/0",
+		"/*",
+		"/items/01",
+	}
+	p, err := jscan.CompilePatterns(patterns...)
+	require.NoError(t, err)
+	require.Equal(t, len(patterns), p.Len())
+	for i := range patterns {
+		require.Equal(t, patterns[i], p.Pattern(i))
+	}
+
+	testMatch(t, p, string(input))
+	testMatch(t, p, []byte(input))
+}
+
+func testMatch[S ~string | ~[]byte](t *testing.T, p *jscan.Patterns, input S) {
+	t.Run(testDataType(input), func(t *testing.T) {
+		var actual []string
+		err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+			actual = append(actual, fmt.Sprintf("%s: %d", i.Pointer(), i.Match(p)))
+			return false
+		})
+		require.False(t, err.IsErr(), "unexpected error: %s", err)
+		require.Equal(t, []string{
+			": 0",
+			"/items: 8",
+			"/items/0: -1",
+			"/items/0/price: 1",
+			"/items/0/name: -1",
+			"/items/1: -1",
+			"/items/1/price: 1",
+			"/items/1/tags: -1",
+			"/items/1/tags/0: 3",
+			"/items/1/tags/1: 3",
+			"/meta: 2",
+			"/meta/a~1b: 2",
+			"/meta/a~1b/~0: 2",
+			"/meta/a~1b/~0/0: 2",
+			"/meta/0: 2",
+			"/escaped: 6",
+			"/price: 4",
+		}, actual)
+	})
+}
+
+func TestRouter(t *testing.T) {
+	const input = `{
+		"items": [
+			{"price": 1, "skipped": {"deep": [1, 2, 3]}},
+			{"price": 2}
+		],
+		"meta": {"x": [true, {"y": null}]},
+		"ignored": [[[[]]]]
+	}`
+	testRouter(t, string(input))
+	testRouter(t, []byte(input))
+}
+
+func testRouter[S ~string | ~[]byte](t *testing.T, input S) {
+	t.Run(testDataType(input), func(t *testing.T) {
+		var visited, actual []string
+		handler := func(name string) func(*jscan.Iterator[S]) bool {
+			return func(i *jscan.Iterator[S]) bool {
+				actual = append(actual, name+" "+string(i.Pointer()))
+				return false
+			}
+		}
+		routes := map[string]func(*jscan.Iterator[S]) bool{
+			"/items/*/price": handler("price"),
+			"/meta/**":       handler("meta"),
+			"/**/price":      handler("anyprice"),
+		}
+		r, err := jscan.NewRouter(routes)
+		require.NoError(t, err)
+
+		// Record all visited values by matching every value to "/**"
+		// which must not affect the routes.
+		all, err := jscan.CompilePatterns("/**")
+		require.NoError(t, err)
+		routes["/**"] = func(i *jscan.Iterator[S]) bool {
+			require.Equal(t, 0, i.Match(all))
+			visited = append(visited, string(i.Pointer()))
+			return false
+		}
+
+		require.False(t, r.Scan(input).IsErr())
+		expect := []string{
+			"anyprice /items/0/price",
+			"price /items/0/price",
+			"anyprice /items/1/price",
+			"price /items/1/price",
+			"meta /meta",
+			"meta /meta/x",
+			"meta /meta/x/0",
+			"meta /meta/x/1",
+			"meta /meta/x/1/y",
+		}
+		require.Equal(t, expect, actual)
+		require.Nil(t, visited)
+
+		actual = nil
+		p := jscan.NewParser[S](64)
+		require.False(t, r.ScanParser(p, input).IsErr())
+		require.Equal(t, expect, actual)
+
+		actual = nil
+		require.NoError(t, jscan.ScanRoutes(input, routes))
+		require.Len(t, visited, 20)
+		require.Equal(t, expect, actual)
+
+		// Stop on error. Handlers are called in the lexicographical order
+		// of their patterns, hence "/items/*" precedes "/items/1".
+		actual = nil
+		err = jscan.ScanRoutes(input, map[string]func(*jscan.Iterator[S]) bool{
+			"/items/1": func(i *jscan.Iterator[S]) bool { return true },
+			"/items/*": handler("item"),
+		})
+		var e jscan.Error[S]
+		require.True(t, errors.As(err, &e))
+		require.Equal(t, jscan.ErrorCodeCallback, e.Code)
+		require.Equal(t, []string{"item /items/0", "item /items/1"}, actual)
+	})
+}
+
+func TestScanRoutesInvalidPattern(t *testing.T) {
+	err := jscan.ScanRoutes(`{}`, map[string]func(*jscan.Iterator[string]) bool{
+		"x": func(*jscan.Iterator[string]) bool { return false },
+	})
+	require.EqualError(t, err, `pattern "x": must either be empty or begin with '/'`)
+}
+
+func BenchmarkMatch(b *testing.B) {
+	input := `{"items":[` + strings.Repeat(`{"price":1,"name":"x"},`, 99) +
+		`{"price":1,"name":"x"}]}`
+	p, err := jscan.CompilePatterns("/items/*/price", "/meta/**")
+	require.NoError(b, err)
+	parser := jscan.NewParser[string](16)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		c := 0
+		parser.Scan(input, func(i *jscan.Iterator[string]) (err bool) {
+			if i.Match(p) != -1 {
+				c++
+			}
+			return false
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package jscan
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math"
+)
+
+var (
+	// ErrInvalidIndex is returned by LoadIndex and LoadLineIndex
+	// when the data isn't a valid binary encoded index.
+	ErrInvalidIndex = errors.New("invalid index")
+
+	// ErrIndexMismatch is returned by LoadIndex and LoadLineIndex
+	// when the index wasn't built for the given source.
+	ErrIndexMismatch = errors.New("index doesn't match source")
+)
+
+// Binary index encoding formats.
+const (
+	indexFormatIndex     = 1
+	indexFormatLineIndex = 2
+)
+
+// crcTable is used for checksums of indexed sources.
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// checksum returns the CRC-32C checksum of s.
+func checksum[S ~string | ~[]byte](s S) uint32 {
+	return crc32.Checksum(unsafeBytes(s), crcTable)
+}
+
+// appendIndexHeader appends the header of a binary index encoding
+// identifying the format and the source.
+func appendIndexHeader(b []byte, format byte, srcLen int, crc uint32) []byte {
+	b = append(b, format)
+	b = binary.AppendUvarint(b, uint64(srcLen))
+	return binary.BigEndian.AppendUint32(b, crc)
+}
+
+// indexDecoder decodes binary index encodings.
+// Once an error was encountered all subsequent reads return zero values.
+type indexDecoder struct {
+	b   []byte
+	err error
+}
+
+// readIndexHeader reads and verifies the header of a binary index encoding
+// of the given format against src and returns the checksum of src.
+func readIndexHeader[S ~string | ~[]byte](
+	d *indexDecoder, format byte, src S,
+) (crc uint32, err error) {
+	if len(d.b) < 1 || d.b[0] != format {
+		return 0, ErrInvalidIndex
+	}
+	d.b = d.b[1:]
+	srcLen := d.uvarint()
+	if len(d.b) < 4 {
+		return 0, ErrInvalidIndex
+	}
+	crc = binary.BigEndian.Uint32(d.b)
+	d.b = d.b[4:]
+	if d.err != nil {
+		return 0, d.err
+	}
+	if srcLen != len(src) || crc != checksum(src) {
+		return 0, ErrIndexMismatch
+	}
+	return crc, nil
+}
+
+// uvarint reads an unsigned integer that fits into int.
+func (d *indexDecoder) uvarint() int {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 || v > uint64(maxInt) {
+		d.err, d.b = ErrInvalidIndex, nil
+		return 0
+	}
+	d.b = d.b[n:]
+	return int(v)
+}
+
+// bytes reads n bytes.
+func (d *indexDecoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > len(d.b) {
+		d.err, d.b = ErrInvalidIndex, nil
+		return nil
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v
+}
+
+// count reads the number of items of a list where each item is encoded by
+// at least minSize bytes, which prevents excessive allocations.
+func (d *indexDecoder) count(minSize int) int {
+	n := d.uvarint()
+	if d.err == nil && n > len(d.b)/minSize {
+		d.err, d.b = ErrInvalidIndex, nil
+		return 0
+	}
+	return n
+}
+
+// MarshalBinary encodes x into a compact binary form that LoadIndex
+// can restore x from without rescanning the source.
+// The encoding contains a checksum of the source but not the source itself.
+func (x *Index[S]) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 16+len(x.typ)*8)
+	b = appendIndexHeader(b, indexFormatIndex, len(x.src), checksum(x.src))
+	b = binary.AppendUvarint(b, uint64(len(x.typ)))
+	prevStart := uint32(0)
+	for n := range x.typ {
+		b = append(b, byte(x.typ[n]))
+		b = binary.AppendUvarint(b, uint64(x.start[n]-prevStart))
+		b = binary.AppendUvarint(b, uint64(x.end[n]-x.start[n]))
+		prevStart = x.start[n]
+		// Keys always precede their values, zero indicates no key.
+		if x.key[n] == noKey {
+			b = append(b, 0)
+		} else {
+			b = binary.AppendUvarint(b, uint64(x.start[n]-x.key[n]))
+		}
+		// Parents always precede their children, one refers to the
+		// previous node which is -1 for the root.
+		b = binary.AppendUvarint(b, uint64(n-x.Parent(n)))
+	}
+	return b, nil
+}
+
+// LoadIndex restores the index of src from data encoded by
+// (*Index).MarshalBinary.
+// Returns ErrInvalidIndex if data isn't a valid binary encoded index
+// and ErrIndexMismatch if the index wasn't built for src.
+// Verifying the checksum of src is much faster than rebuilding the index.
+func LoadIndex[S ~string | ~[]byte](src S, data []byte) (*Index[S], error) {
+	d := &indexDecoder{b: data}
+	if _, err := readIndexHeader(d, indexFormatIndex, src); err != nil {
+		return nil, err
+	}
+	if uint64(len(src)) > math.MaxUint32 {
+		return nil, ErrInvalidIndex
+	}
+	// Every node is encoded by at least 5 bytes.
+	l := d.count(5)
+	x := &Index[S]{
+		src:    src,
+		typ:    make([]ValueType, l),
+		start:  make([]uint32, l),
+		end:    make([]uint32, l),
+		key:    make([]uint32, l),
+		parent: make([]uint32, l),
+		child:  make([]uint32, l, l+1),
+	}
+	prevStart := 0
+	for n := 0; n < l && d.err == nil; n++ {
+		t := d.bytes(1)
+		if t == nil || t[0] < byte(ValueTypeObject) || t[0] > byte(ValueTypeNumber) {
+			return nil, ErrInvalidIndex
+		}
+		x.typ[n] = ValueType(t[0])
+		start := prevStart + d.uvarint()
+		end := start + d.uvarint()
+		key := -1
+		if k := d.uvarint(); k != 0 {
+			key = start - k
+		}
+		parent := n - d.uvarint()
+		if !x.validNode(n, start, end, key, parent) {
+			return nil, ErrInvalidIndex
+		}
+		prevStart = start
+		x.start[n], x.end[n], x.key[n] = uint32(start), uint32(end), noKey
+		if key != -1 {
+			x.key[n] = uint32(key)
+		}
+		if n > 0 {
+			x.parent[n] = uint32(parent)
+			x.child[parent]++
+		}
+	}
+	if d.err != nil || len(d.b) > 0 || l == 0 {
+		return nil, ErrInvalidIndex
+	}
+	x.buildChildren()
+	return x, nil
+}
+
+// validNode returns true if node n that is being decoded is consistent
+// with the source and the nodes preceding it.
+// key is -1 if n isn't a member.
+func (x *Index[S]) validNode(n, start, end, key, parent int) bool {
+	if start < 0 || end <= start || end > len(x.src) {
+		return false
+	}
+	if n == 0 {
+		return parent == -1 && key == -1
+	}
+	if parent < 0 || parent >= n ||
+		start <= int(x.start[parent]) || end >= int(x.end[parent]) {
+		return false
+	}
+	switch x.typ[parent] {
+	case ValueTypeObject:
+		// Members must have a key of at least two quotation marks
+		// followed by a colon surrounded by optional whitespace.
+		if key <= int(x.start[parent]) || key >= start || x.src[key] != '"' {
+			return false
+		}
+		j := start - 1
+		for j > key && lutSX[x.src[j]] == 1 {
+			j--
+		}
+		if j <= key || x.src[j] != ':' {
+			return false
+		}
+		for j--; j > key && lutSX[x.src[j]] == 1; j-- {
+			// Skip whitespace between the key and the colon.
+		}
+		if j <= key || x.src[j] != '"' {
+			return false
+		}
+	case ValueTypeArray:
+		if key != -1 {
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package jscan_test
+
+import (
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadIndex(t *testing.T) {
+	for _, input := range []string{
+		`42`,
+		` {} `,
+		`{"a":[1,{"b":null},[]],"c\/d":{"e~f":"g","":true},"h":-1.5}`,
+		`[[[0]],{"x":{"y":[0,1,{"z":"w"}]}},"s",false]`,
+		"{ \"a\" :\t[ 1 , 2 ] ,\n\"b\"\n:\n{ } }",
+	} {
+		t.Run(input, func(t *testing.T) {
+			x, err := jscan.BuildIndex(input)
+			require.False(t, err.IsErr())
+			b, errMarshal := x.MarshalBinary()
+			require.NoError(t, errMarshal)
+
+			l, errLoad := jscan.LoadIndex(input, b)
+			require.NoError(t, errLoad)
+			require.Equal(t, x, l)
+
+			lb, errLoad := jscan.LoadIndex([]byte(input), b)
+			require.NoError(t, errLoad)
+			require.Equal(t, x.Nodes(), lb.Nodes())
+		})
+	}
+}
+
+func TestLoadIndexErr(t *testing.T) {
+	const input = `{"a":[1,{"b":null},[]],"c" : {"d":"e"}}`
+	x, err := jscan.BuildIndex(input)
+	require.False(t, err.IsErr())
+	b, errMarshal := x.MarshalBinary()
+	require.NoError(t, errMarshal)
+
+	t.Run("mismatch", func(t *testing.T) {
+		for _, src := range []string{
+			`{"a":[1,{"b":null},[]],"c" : {"d":"f"}}`,
+			`{"a":[1,{"b":null},[]],"c" : {"d":"e"}} `,
+			``,
+		} {
+			_, err := jscan.LoadIndex(src, b)
+			require.ErrorIs(t, err, jscan.ErrIndexMismatch, "src: %q", src)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := jscan.LoadIndex(input, nil)
+		require.ErrorIs(t, err, jscan.ErrInvalidIndex)
+
+		_, err = jscan.LoadIndex(input, append(b, 0))
+		require.ErrorIs(t, err, jscan.ErrInvalidIndex)
+
+		lb, errLine := (&jscan.LineIndex{}).MarshalBinary()
+		require.NoError(t, errLine)
+		_, err = jscan.LoadIndex(input, lb)
+		require.ErrorIs(t, err, jscan.ErrInvalidIndex)
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		// Corrupting any byte following the header
+		// must never cause a panic.
+		for j := 6; j < len(b); j++ {
+			for _, c := range []byte{0, 1, 2, 0x7f, 0xff} {
+				d := append([]byte(nil), b...)
+				d[j] = c
+				if x, err := jscan.LoadIndex(input, d); err == nil {
+					for n := 0; n < x.Nodes(); n++ {
+						_ = x.Value(n)
+						_ = x.Key(n)
+						_ = x.Elem(n, x.Len(n)-1)
+						_ = x.NextSibling(n)
+						_ = x.FirstChild(n)
+					}
+					_, _ = x.Lookup("/a/1/b")
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package jscan
+
+// minCarryExtension is the minimum number of bytes of a chunk appended
+// to the bytes carried over from the previous chunks at a time.
+const minCarryExtension = 64
+
+// PushParser scans JSON pushed to it in chunks of arbitrary size,
+// such as the body of a chunked HTTP response or WebSocket frames,
+// see NewPushParser.
+type PushParser struct {
+	s streamScanner
+
+	// carry holds the bytes of the previous chunks following the last
+	// checkpoint, pos is the index of the following chunk in the stream.
+	carry []byte
+	pos   int
+}
+
+// NewPushParser creates a new parser calling fn for every encountered value
+// including objects and arrays as soon as it's read.
+//
+// The callback model is the same as with (*StreamParser).Scan.
+// Tokens may be split across chunks arbitrarily, Key and Value of
+// the current value remain valid during the callback in any case.
+// Chunks are scanned directly, only the bytes following the last value
+// are copied when a chunk ends.
+func NewPushParser(fn func(*Iterator[[]byte]) (err bool)) *PushParser {
+	p := new(PushParser)
+	p.s.init()
+	p.restart(fn)
+	return p
+}
+
+// Reset resets p to read a new value reusing its buffers.
+func (p *PushParser) Reset() {
+	p.restart(p.s.fn)
+}
+
+// restart prepares p for reading a new value calling fn.
+func (p *PushParser) restart(fn func(*Iterator[[]byte]) (err bool)) {
+	p.s.begin(fn, false)
+	p.s.eof, p.s.err = false, Error[[]byte]{}
+	p.carry, p.pos = p.carry[:0], 0
+}
+
+// release drops the references to the callback and the chunks of the caller
+// before p is put back into the pool.
+func (p *PushParser) release() {
+	p.s.fn, p.s.i.src, p.s.err = nil, nil, Error[[]byte]{}
+}
+
+// Feed reads chunk, which directly follows the previously fed chunks,
+// and calls fn for all values completed by it.
+// chunk isn't retained and may be reused by the caller after Feed returns,
+// except for the Src of the returned error.
+// Returns an error of type Error[[]byte] if the input is invalid JSON
+// in which case subsequent calls to Feed and Close return the same error.
+func (p *PushParser) Feed(chunk []byte) error {
+	if p.feed(chunk); p.s.err.IsErr() {
+		return p.s.err
+	}
+	return nil
+}
+
+// Close reports the end of the input. Returns an error of type
+// Error[[]byte] if the input is incomplete or invalid JSON.
+func (p *PushParser) Close() error {
+	if err := p.close(); err.IsErr() {
+		return err
+	}
+	return nil
+}
+
+// feed scans chunk. Unless carry is empty, chunk is appended to it
+// in parts of growing size until the checkpoint moves into chunk,
+// which is then scanned directly.
+func (p *PushParser) feed(chunk []byte) {
+	s, r := &p.s, 0
+	for len(p.carry) > 0 && !s.err.IsErr() {
+		if r == len(chunk) {
+			p.pos += len(chunk)
+			return
+		}
+		l := len(p.carry)
+		n := min(len(chunk)-r, max(l, minCarryExtension))
+		if s.inString {
+			// Don't scan the string again unless it may end.
+			j := r
+			for j < len(chunk) && (lutStr[chunk[j]] == 0 || chunk[j] == '\\') {
+				j++
+			}
+			if j == len(chunk) {
+				p.carry = append(p.carry, chunk[r:]...)
+				p.pos += len(chunk)
+				return
+			}
+			n = min(len(chunk)-r, max(n, j-r+1))
+		}
+		p.carry = append(p.carry, chunk[r:r+n]...)
+		offset := p.pos + r - l
+		s.remap, s.remapOffset = chunk, p.pos
+		c := s.run(p.carry, offset, 0)
+		s.remap = nil
+		if c >= l {
+			s.saveKeys(p.pos)
+			p.carry, r = p.carry[:0], r+c-l
+			break
+		}
+		s.saveKeys(offset + c)
+		p.carry = append(p.carry[:0], p.carry[c:]...)
+		r += n
+	}
+	if s.err.IsErr() {
+		return
+	}
+	c := s.run(chunk, p.pos, r)
+	s.saveKeys(p.pos + c)
+	p.carry = append(p.carry, chunk[c:]...)
+	p.pos += len(chunk)
+}
+
+// close scans the carried bytes as the end of the input.
+func (p *PushParser) close() Error[[]byte] {
+	s := &p.s
+	s.eof = true
+	s.run(p.carry, p.pos-len(p.carry), 0)
+	return s.err
+}
@@ -0,0 +1,99 @@
+package jscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushParser(t *testing.T) {
+	for _, input := range streamTestInputs {
+		t.Run(input, func(t *testing.T) {
+			var expect []streamRecord
+			expectErr := jscan.NewParserWithOptions[string](
+				64, jscan.ParserOptions{TrackPointer: true},
+			).Scan(input, func(i *jscan.Iterator[string]) (err bool) {
+				expect = append(expect, newStreamRecord(i))
+				return false
+			})
+
+			for _, chunkSize := range []int{1, 2, 3, 5, 16, len(input) + 1} {
+				var actual []streamRecord
+				p := jscan.NewPushParser(func(i *jscan.Iterator[[]byte]) (err bool) {
+					actual = append(actual, newStreamRecord(i))
+					return false
+				})
+				err := feedChunks(p, input, chunkSize)
+				require.Equal(t, expect, actual, "chunkSize: %d", chunkSize)
+				requireStreamErr(t, expectErr, err)
+			}
+		})
+	}
+}
+
+// feedChunks feeds input to p in chunks of chunkSize bytes
+// reusing the chunk buffer and closes p.
+func feedChunks(p *jscan.PushParser, input string, chunkSize int) error {
+	chunk := make([]byte, chunkSize)
+	for len(input) > 0 {
+		n := copy(chunk, input)
+		input = input[n:]
+		if err := p.Feed(chunk[:n]); err != nil {
+			return err
+		}
+		// Overwrite the chunk to make sure it's not retained.
+		for i := range chunk {
+			chunk[i] = 0xff
+		}
+	}
+	return p.Close()
+}
+
+func TestPushParserDelivery(t *testing.T) {
+	var values []string
+	p := jscan.NewPushParser(func(i *jscan.Iterator[[]byte]) (err bool) {
+		values = append(values, string(i.Key())+"="+string(i.Value()))
+		return false
+	})
+	require.NoError(t, p.Feed([]byte(`{`)))
+	require.Empty(t, values)
+	require.NoError(t, p.Feed([]byte(`"ke`)))
+	require.Equal(t, []string{"="}, values)
+	require.NoError(t, p.Feed([]byte(`y":"val`)))
+	require.Equal(t, []string{"="}, values)
+	require.NoError(t, p.Feed([]byte(`ue","n":12`)))
+	require.Equal(t, []string{"=", `"key"="value"`}, values)
+	require.NoError(t, p.Feed([]byte(`3}`)))
+	require.Equal(t, []string{"=", `"key"="value"`, `"n"=123`}, values)
+	require.NoError(t, p.Close())
+
+	p.Reset()
+	values = nil
+	require.NoError(t, p.Feed([]byte(`-1`)))
+	require.Empty(t, values, "the number may continue")
+	require.NoError(t, p.Close())
+	require.Equal(t, []string{"=-1"}, values)
+}
+
+func TestPushParserErr(t *testing.T) {
+	p := jscan.NewPushParser(func(i *jscan.Iterator[[]byte]) (err bool) {
+		return false
+	})
+	require.NoError(t, p.Feed([]byte(`[1,`)))
+	err := p.Feed([]byte(`]`))
+	var e jscan.Error[[]byte]
+	require.True(t, errors.As(err, &e))
+	require.Equal(t, jscan.ErrorCodeUnexpectedToken, e.Code)
+	require.Equal(t, 3, e.Index)
+	require.Equal(t, err, p.Feed([]byte(`2]`)))
+	require.Equal(t, err, p.Close())
+
+	p.Reset()
+	require.NoError(t, p.Feed([]byte(`[`)))
+	err = p.Close()
+	require.True(t, errors.As(err, &e))
+	require.Equal(t, jscan.ErrorCodeUnexpectedEOF, e.Code)
+	require.Equal(t, 1, e.Index)
+}
@@ -0,0 +1,181 @@
+package jscan
+
+import "github.com/romshark/jscan/v2/internal/strfind"
+
+type readerState int8
+
+const (
+	// readerStateValue expects a value.
+	readerStateValue readerState = iota
+
+	// readerStateObject expects the first key or the end of the object
+	// that was read last.
+	readerStateObject
+
+	// readerStateArray expects the first element or the end of the array
+	// that was read last.
+	readerStateArray
+
+	// readerStateSkipped expects the end event of the object or array
+	// the children of which were skipped.
+	readerStateSkipped
+
+	// readerStateAfterValue expects either a comma, the end of the parent
+	// object or array or the end of the input.
+	readerStateAfterValue
+
+	// readerStateDone indicates that either the end of the input
+	// or an error was reached.
+	readerStateDone
+)
+
+// Reader is a pull-style alternative to Scan.
+// Every call to Next advances the reader to the next value in the same order
+// in which Scan would invoke its callback.
+// The embedded Iterator provides access to the current value.
+//
+// WARNING: Don't use or alias the embedded Iterator after the next call to Next!
+type Reader[S ~string | ~[]byte] struct {
+	Iterator[S]
+
+	// pos is the index in the source reading continues at
+	// in readerStateSkipped and readerStateAfterValue.
+	pos   int
+	state readerState
+	err   Error[S]
+}
+
+// NewReader creates a new reader reading s.
+func NewReader[S ~string | ~[]byte](s S) *Reader[S] {
+	return NewReaderWithOptions(s, ParserOptions{})
+}
+
+// NewReaderWithOptions is similar to NewReader but
+// additionally enables the optional features selected in o.
+func NewReaderWithOptions[S ~string | ~[]byte](s S, o ParserOptions) *Reader[S] {
+	r := &Reader[S]{Iterator: Iterator[S]{
+		stack:        make([]stackNode, 0, DefaultStackSizeIterator),
+		skipStack:    make([]stackNodeType, 0, DefaultStackSizeValidator),
+		endEvents:    o.EndEvents,
+		trackPointer: o.TrackPointer,
+		pull:         true,
+	}}
+	r.Reset(s)
+	return r
+}
+
+// Reset resets the reader to read s from the beginning.
+// Reusing a reader is more efficient than creating a new one.
+func (r *Reader[S]) Reset(s S) {
+	reset(&r.Iterator)
+	r.src, r.pos = s, 0
+	r.state, r.err = readerStateValue, Error[S]{}
+}
+
+// Err returns the error encountered by Next, if any.
+func (r *Reader[S]) Err() Error[S] { return r.err }
+
+// Skip skips all member and element values of the current object or array
+// such that the next call to Next won't return any of them.
+// Skipped values are still validated.
+// Skip has no effect if the current value is neither an object nor an array.
+func (r *Reader[S]) Skip() {
+	if r.state != readerStateObject && r.state != readerStateArray {
+		return
+	}
+	t, err := r.skipValue()
+	if err.IsErr() {
+		r.fail(err)
+		return
+	}
+	r.pos, r.state = len(r.src)-len(t), readerStateAfterValue
+	if r.endEvents {
+		var nt stackNodeType = stackNodeTypeObject
+		if r.valueType == ValueTypeArray {
+			nt = stackNodeTypeArray
+		}
+		r.push(nt, r.keyIndex, r.keyIndexEnd, -1)
+		if r.trackPointer {
+			r.markPointer()
+		}
+		r.state = readerStateSkipped
+	}
+}
+
+// Next advances the reader to the next value and returns true,
+// otherwise returns false if either the end of the input or an error
+// was reached. Use Err to check for errors.
+//
+// Next resumes scan, which stops at the next value since the callback
+// always returns true. Objects and arrays are pushed onto the stack
+// by Next since scan stops before pushing them.
+func (r *Reader[S]) Next() bool {
+	if r.skip {
+		// The current object or array was captured using CaptureRaw.
+		r.Skip()
+	}
+	i := &r.Iterator
+	switch r.state {
+	case readerStateValue:
+		i.resumeState = 0
+	case readerStateObject, readerStateArray:
+		var t stackNodeType = stackNodeTypeObject
+		c := checkpointStateObject
+		if r.state == readerStateArray {
+			t, c = stackNodeTypeArray, checkpointStateArray
+		}
+		i.push(t, i.keyIndex, i.keyIndexEnd, 0)
+		if i.trackPointer {
+			i.markPointer()
+		}
+		i.resumeState, i.resumeOffset = c, i.valueIndex+1
+	case readerStateSkipped:
+		i.popEnd(r.pos)
+		r.state = readerStateAfterValue
+		return true
+	case readerStateAfterValue:
+		i.resumeState, i.resumeOffset = checkpointStateAfterValue, r.pos
+	default:
+		return false
+	}
+	i.keyIndex, i.keyIndexEnd = -1, -1
+	i.end, i.len = false, -1
+	i.valueType = 0
+
+	// Since i.pull is set, scan doesn't return an error when stopped,
+	// instead the value type tells whether a value was reached.
+	t, err := scanValues[S, scanOptional](i, stopAtValue[S])
+	switch {
+	case err.IsErr():
+		return r.fail(err)
+	case i.valueType != 0:
+		r.pos, r.state = i.valueIndexEnd, readerStateAfterValue
+		if !i.end {
+			switch i.valueType {
+			case ValueTypeObject:
+				r.state = readerStateObject
+			case ValueTypeArray:
+				r.state = readerStateArray
+			}
+		}
+		return true
+	}
+	var illegalChar bool
+	if t, illegalChar = strfind.EndOfWhitespaceSeq(t); illegalChar {
+		return r.fail(getError(ErrorCodeIllegalControlChar, i.src, t))
+	}
+	if len(t) > 0 {
+		return r.fail(getError(ErrorCodeUnexpectedToken, i.src, t))
+	}
+	r.pos, r.state = len(i.src), readerStateDone
+	return false
+}
+
+// stopAtValue is the callback stopping scan at the first value.
+func stopAtValue[S ~string | ~[]byte](*Iterator[S]) (err bool) { return true }
+
+// fail records err and stops the reader. Always returns false.
+func (r *Reader[S]) fail(err Error[S]) bool {
+	r.err, r.state = err, readerStateDone
+	return false
+}
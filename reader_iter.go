@@ -0,0 +1,39 @@
+//go:build go1.23
+
+package jscan
+
+import "iter"
+
+// All returns an iterator over the remaining values of r.
+// Check (*Reader).Err for errors after the iteration is over.
+//
+// WARNING: Don't use or alias *Iterator[S] after the next iteration!
+func (r *Reader[S]) All() iter.Seq[*Iterator[S]] {
+	return func(yield func(*Iterator[S]) bool) {
+		for r.Next() {
+			if !yield(&r.Iterator) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over all values in s in the order
+// in which Scan would invoke its callback.
+// If an error is encountered then it's yielded together with
+// a nil iterator as the last pair.
+//
+// WARNING: Don't use or alias *Iterator[S] after the next iteration!
+func Values[S ~string | ~[]byte](s S) iter.Seq2[*Iterator[S], Error[S]] {
+	return func(yield func(*Iterator[S], Error[S]) bool) {
+		r := NewReader(s)
+		for r.Next() {
+			if !yield(&r.Iterator, Error[S]{}) {
+				return
+			}
+		}
+		if err := r.Err(); err.IsErr() {
+			yield(nil, err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+//go:build go1.23
+
+package jscan_test
+
+import (
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReaderAll(t *testing.T) {
+	var pointers []string
+	r := jscan.NewReader(`{"a":[1,2],"b":{"c":null}}`)
+	for i := range r.All() {
+		pointers = append(pointers, i.Pointer())
+		if i.Pointer() == "/b" {
+			break
+		}
+	}
+	require.False(t, r.Err().IsErr(), "unexpected error: %s", r.Err())
+	require.Equal(t, []string{"", "/a", "/a/0", "/a/1", "/b"}, pointers)
+}
+
+func TestValues(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var values []string
+		for i, err := range jscan.Values([]byte(`[1,"x",[true]]`)) {
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			values = append(values, string(i.Value()))
+		}
+		require.Equal(t, []string{"", "1", `"x"`, "", "true"}, values)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var values []string
+		var err jscan.Error[string]
+		for i, e := range jscan.Values(`[1,2,}`) {
+			if e.IsErr() {
+				require.Nil(t, i)
+				err = e
+				break
+			}
+			values = append(values, i.Value())
+		}
+		require.Equal(t, []string{"", "1", "2"}, values)
+		require.Equal(t, jscan.ErrorCodeUnexpectedToken, err.Code)
+		require.Equal(t, len(`[1,2,`), err.Index)
+	})
+}
@@ -0,0 +1,177 @@
+package jscan_test
+
+import (
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var readerTestInputs = []string{
+	`null`,
+	` 42 `,
+	`"A\n"`,
+	`{}`,
+	`[]`,
+	`{"a":[1,{}],"b":{"c":[ ]},"d":[[2]],"e":"\"x\"","f":-1.5e3}`,
+	`[true,false,null,{"a/b~":"x"},[[]]]`,
+	"{\n\t\"x\" : [ 1 , 2 ] \r\n}",
+	// Invalid inputs
+	``,
+	`{`,
+	`[`,
+	`{"a"`,
+	`{"a":}`,
+	`[1,]`,
+	`[1}`,
+	`{"a":1]`,
+	`{1:1}`,
+	`[tru]`,
+	`[-]`,
+	`["\x"]`,
+	"[\"\x01\"]",
+	`[1] 2`,
+	"[1]\x01",
+}
+
+// readerRecord builds a Record from an iterator.
+func readerRecord[S ~string | ~[]byte](i *jscan.Iterator[S]) Record {
+	return Record{
+		Level:      i.Level(),
+		ValueType:  i.ValueType(),
+		Key:        string(i.Key()),
+		Value:      string(i.Value()),
+		ArrayIndex: i.ArrayIndex(),
+		Pointer:    string(i.Pointer()),
+	}
+}
+
+func TestReader(t *testing.T) {
+	for _, input := range readerTestInputs {
+		t.Run(input, func(t *testing.T) {
+			testReader(t, string(input), jscan.ParserOptions{})
+			testReader(t, []byte(input), jscan.ParserOptions{})
+			testReader(t, string(input), jscan.ParserOptions{EndEvents: true})
+			testReader(t, []byte(input), jscan.ParserOptions{EndEvents: true})
+			testReader(t, string(input), jscan.ParserOptions{
+				EndEvents:    true,
+				TrackPointer: true,
+			})
+		})
+	}
+}
+
+func testReader[S ~string | ~[]byte](
+	t *testing.T, input S, o jscan.ParserOptions,
+) {
+	var expect []Record
+	var expectEnds, expectEscapes []bool
+	expectErr := jscan.NewParserWithOptions[S](64, o).Scan(
+		input, func(i *jscan.Iterator[S]) (err bool) {
+			expect = append(expect, readerRecord(i))
+			expectEnds = append(expectEnds, i.IsEnd())
+			expectEscapes = append(expectEscapes, i.ValueHasEscapes())
+			return false
+		},
+	)
+
+	var actual []Record
+	var actualEnds, actualEscapes []bool
+	r := jscan.NewReaderWithOptions(input, o)
+	for r.Next() {
+		actual = append(actual, readerRecord(&r.Iterator))
+		actualEnds = append(actualEnds, r.IsEnd())
+		actualEscapes = append(actualEscapes, r.ValueHasEscapes())
+	}
+	require.Equal(t, expect, actual)
+	require.Equal(t, expectEnds, actualEnds)
+	require.Equal(t, expectEscapes, actualEscapes)
+	require.Equal(t, expectErr, r.Err())
+	require.False(t, r.Next(), "Next after the end")
+}
+
+func TestReaderSkip(t *testing.T) {
+	const input = `{"a":{"x":[1,2]},"b":[true,[null]],"c":"d","e":{}}`
+
+	t.Run("Skip", func(t *testing.T) {
+		var pointers []string
+		r := jscan.NewReader(input)
+		for r.Next() {
+			pointers = append(pointers, r.Pointer())
+			if r.Level() == 1 {
+				r.Skip()
+			}
+		}
+		require.False(t, r.Err().IsErr(), "unexpected error: %s", r.Err())
+		require.Equal(t, []string{"", "/a", "/b", "/c", "/e"}, pointers)
+	})
+
+	t.Run("SkipEndEvents", func(t *testing.T) {
+		var records []EndRecord
+		r := jscan.NewReaderWithOptions(input, jscan.ParserOptions{
+			EndEvents:    true,
+			TrackPointer: true,
+		})
+		for r.Next() {
+			records = append(records, EndRecord{
+				End:       r.IsEnd(),
+				ValueType: r.ValueType(),
+				Pointer:   r.Pointer(),
+				Len:       r.Len(),
+			})
+			if r.Level() == 1 {
+				r.Skip()
+			}
+		}
+		require.False(t, r.Err().IsErr(), "unexpected error: %s", r.Err())
+
+		O, A, S := jscan.ValueTypeObject, jscan.ValueTypeArray, jscan.ValueTypeString
+		require.Equal(t, []EndRecord{
+			{End: false, ValueType: O, Pointer: "", Len: -1},
+			{End: false, ValueType: O, Pointer: "/a", Len: -1},
+			{End: true, ValueType: O, Pointer: "/a", Len: -1},
+			{End: false, ValueType: A, Pointer: "/b", Len: -1},
+			{End: true, ValueType: A, Pointer: "/b", Len: -1},
+			{End: false, ValueType: S, Pointer: "/c", Len: -1},
+			{End: false, ValueType: O, Pointer: "/e", Len: -1},
+			{End: true, ValueType: O, Pointer: "/e", Len: -1},
+			{End: true, ValueType: O, Pointer: "", Len: 4},
+		}, records)
+	})
+
+	t.Run("CaptureRaw", func(t *testing.T) {
+		var raw []string
+		r := jscan.NewReader(input)
+		for r.Next() {
+			if r.Level() == 1 {
+				raw = append(raw, r.CaptureRaw())
+			}
+		}
+		require.False(t, r.Err().IsErr(), "unexpected error: %s", r.Err())
+		require.Equal(t, []string{`{"x":[1,2]}`, `[true,[null]]`, `"d"`, `{}`}, raw)
+	})
+
+	t.Run("ErrorInSkipped", func(t *testing.T) {
+		r := jscan.NewReader(`[{"a":[1,tru]}]`)
+		require.True(t, r.Next())
+		require.True(t, r.Next())
+		r.Skip()
+		require.False(t, r.Next())
+		require.Equal(t, jscan.ErrorCodeUnexpectedToken, r.Err().Code)
+		require.Equal(t, len(`[{"a":[1,`), r.Err().Index)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		r := jscan.NewReader(`[1`)
+		for r.Next() {
+		}
+		require.True(t, r.Err().IsErr())
+		r.Reset(`[1]`)
+		c := 0
+		for r.Next() {
+			c++
+		}
+		require.False(t, r.Err().IsErr(), "unexpected error: %s", r.Err())
+		require.Equal(t, 2, c)
+	})
+}
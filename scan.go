@@ -102,14 +102,50 @@ func Scan[S ~string | ~[]byte](
 // that rely on a global iterator pool.
 type Parser[S ~string | ~[]byte] struct{ i *Iterator[S] }
 
+// ParserOptions configures optional features of a Parser.
+// The zero value disables all optional features.
+type ParserOptions struct {
+	// EndEvents enables invoking the callback once more when the end of
+	// an object or array is reached. See (*Iterator).IsEnd.
+	// The end event of an object or array the children of which were skipped
+	// using ActionSkipChildren or CaptureRaw reports a Len of -1.
+	EndEvents bool
+
+	// TrackPointer enables keeping the JSON pointer of the current value
+	// up to date during traversal instead of rebuilding it from the stack
+	// on every call to (*Iterator).Pointer and (*Iterator).ViewPointer.
+	// This is more efficient if the pointer of most values is needed,
+	// otherwise it's an unnecessary overhead.
+	TrackPointer bool
+
+	// Progress, if not nil, is called periodically by (*Parser).ScanContext
+	// with the number of bytes of the source consumed so far,
+	// and once more with the length of the source when scanning succeeds.
+	Progress func(consumed int)
+}
+
 // NewParser creates a new reusable parser instance.
 // A higher preallocStackFrames value implies greater memory usage but also reduces
 // the chance of dynamic memory allocations if the JSON depth surpasses the stack size.
-// preallocStackFrames of 32 is equivalent to ~1KiB of memory usage on 64-bit systems
-// (1 frame = ~32 bytes).
+// preallocStackFrames of 32 is equivalent to ~1.25KiB of memory usage on 64-bit
+// systems (1 frame = ~40 bytes).
 // Use DefaultStackSizeIterator when not sure.
 func NewParser[S ~string | ~[]byte](preallocStackFrames int) *Parser[S] {
-	i := &Iterator[S]{stack: make([]stackNode, preallocStackFrames)}
+	return NewParserWithOptions[S](preallocStackFrames, ParserOptions{})
+}
+
+// NewParserWithOptions is similar to NewParser but
+// additionally enables the optional features selected in o.
+func NewParserWithOptions[S ~string | ~[]byte](
+	preallocStackFrames int, o ParserOptions,
+) *Parser[S] {
+	i := &Iterator[S]{
+		stack:        make([]stackNode, preallocStackFrames),
+		skipStack:    make([]stackNodeType, 0, DefaultStackSizeValidator),
+		endEvents:    o.EndEvents,
+		trackPointer: o.TrackPointer,
+		progress:     o.Progress,
+	}
 	reset(i)
 	return &Parser[S]{i: i}
 }
@@ -159,18 +195,124 @@ func (p *Parser[S]) Scan(
 	return Error[S]{}
 }
 
+// ScanAction is similar to Scan except that fn returns an Action
+// which, unlike a boolean error flag, also allows skipping the member and
+// element values of objects and arrays using ActionSkipChildren.
+// Skipped values are validated without invoking fn.
+//
+// WARNING: Don't use or alias *Iterator[S] after fn returns!
+func ScanAction[S ~string | ~[]byte](
+	s S, fn func(*Iterator[S]) Action,
+) Error[S] {
+	return Scan(s, actionCallback(fn))
+}
+
+// ScanOneAction is similar to ScanOne except that fn returns an Action.
+// See ScanAction for more details.
+//
+// WARNING: Don't use or alias *Iterator[S] after fn returns!
+func ScanOneAction[S ~string | ~[]byte](
+	s S, fn func(*Iterator[S]) Action,
+) (trailing S, err Error[S]) {
+	return ScanOne(s, actionCallback(fn))
+}
+
+// ScanAction is similar to (*Parser).Scan except that fn returns an Action.
+// See ScanAction for more details.
+//
+// WARNING: Don't use or alias *Iterator[S] after fn returns!
+func (p *Parser[S]) ScanAction(
+	s S, fn func(*Iterator[S]) Action,
+) Error[S] {
+	return p.Scan(s, actionCallback(fn))
+}
+
+// ScanOneAction is similar to (*Parser).ScanOne except that fn returns an Action.
+// See ScanAction for more details.
+//
+// WARNING: Don't use or alias *Iterator[S] after fn returns!
+func (p *Parser[S]) ScanOneAction(
+	s S, fn func(*Iterator[S]) Action,
+) (trailing S, err Error[S]) {
+	return p.ScanOne(s, actionCallback(fn))
+}
+
+// actionCallback adapts fn to the boolean callback used by scan.
+func actionCallback[S ~string | ~[]byte](
+	fn func(*Iterator[S]) Action,
+) func(*Iterator[S]) bool {
+	return func(i *Iterator[S]) bool {
+		switch fn(i) {
+		case ActionSkipChildren:
+			i.skip = !i.end && (i.valueType == ValueTypeObject ||
+				i.valueType == ValueTypeArray)
+		case ActionStop:
+			return true
+		}
+		return false
+	}
+}
+
 // scan calls fn for every value encountered.
 // Returns the remainder of i.src and an error if any is encountered.
 func scan[S ~string | ~[]byte](
 	i *Iterator[S], fn func(*Iterator[S]) (err bool),
 ) (S, Error[S]) {
+	return scanValues[S, scanDefault](i, fn)
+}
+
+// scanDefault and scanOptional select the instantiation of scanValues.
+// Their underlying types differ, hence each gets its own instantiation, in
+// which optional is a constant. This keeps the checks for end events,
+// pointer tracking and resuming out of the default instantiation.
+type (
+	scanDefault  struct{}
+	scanOptional struct{ _ bool }
+	scanMode     interface{ scanDefault | scanOptional }
+)
+
+// optional returns true for the instantiation of scanValues
+// supporting optional features.
+func optional[M scanMode]() bool {
+	_, ok := any(*new(M)).(scanOptional)
+	return ok
+}
+
+// scanValues implements scan, see scanMode.
+func scanValues[S ~string | ~[]byte, M scanMode](
+	i *Iterator[S], fn func(*Iterator[S]) (err bool),
+) (S, Error[S]) {
+	if !optional[M]() && (i.endEvents || i.trackPointer || i.resumeState != 0) {
+		return scanValues[S, scanOptional](i, fn)
+	}
 	var (
 		rollback S // Used as fallback for error report
 		s        = i.src
 		b        bool
 		ks, ke   int
+		err      Error[S]
 	)
 
+	if optional[M]() && i.resumeState != 0 {
+		// Resume from a checkpoint.
+		s = i.src[i.resumeOffset:]
+		r := i.resumeState
+		i.resumeState = 0
+		switch r {
+		case checkpointStateAfterValue:
+			goto AFTER_VALUE
+		case checkpointStateArray:
+			goto VALUE_OR_ARR_TERM
+		}
+		if s, b = strfind.EndOfWhitespaceSeq(s); b {
+			return s, getError(ErrorCodeIllegalControlChar, i.src, s)
+		}
+		if len(s) > 0 && s[0] == '}' {
+			goto AFTER_VALUE
+		}
+		goto OBJ_KEY
+	}
+
 VALUE:
 	if len(s) < 1 {
 		return s, getError(ErrorCodeUnexpectedEOF, i.src, s)
@@ -233,24 +375,58 @@ VALUE_OBJECT:
 		i.arrayIndex = -1
 		if len(i.stack) != 0 &&
 			i.stack[len(i.stack)-1].Type == stackNodeTypeArray {
-			i.arrayIndex = i.stack[len(i.stack)-1].ArrLen
-			i.stack[len(i.stack)-1].ArrLen++
+			i.arrayIndex = i.stack[len(i.stack)-1].Len
+			i.stack[len(i.stack)-1].Len++
 		}
 		if fn(i) {
-			return s, i.getError(ErrorCodeCallback)
+			goto STOP
 		}
 		i.keyIndex = -1
+		if i.skip {
+			if s, err = i.skipValue(); err.IsErr() {
+				return s, err
+			}
+			if optional[M]() && i.endEvents {
+				i.stack = append(i.stack, stackNode{
+					Type:        stackNodeTypeObject,
+					KeyIndex:    ks,
+					KeyIndexEnd: ke,
+					Index:       i.valueIndex,
+					Len:         -1,
+				})
+				if optional[M]() && i.trackPointer {
+					i.markPointer()
+				}
+				if i.invokeEndCallback(fn, len(i.src)-len(s)) {
+					goto STOP
+				}
+			}
+			goto AFTER_VALUE
+		}
 	}
 
 	if s[0] == '}' {
 		s = s[1:]
+		if optional[M]() && i.endEvents {
+			i.stack = append(i.stack, stackNode{
+				Type:        stackNodeTypeObject,
+				KeyIndex:    ks,
+				KeyIndexEnd: ke,
+				Index:       i.valueIndex,
+			})
+			if optional[M]() && i.trackPointer {
+				i.markPointer()
+			}
+			if i.invokeEndCallback(fn, len(i.src)-len(s)) {
+				goto STOP
+			}
+		}
 		goto AFTER_VALUE
 	}
-	i.stack = append(i.stack, stackNode{
-		Type:        stackNodeTypeObject,
-		KeyIndex:    ks,
-		KeyIndexEnd: ke,
-	})
+	i.push(stackNodeTypeObject, ks, ke, 0)
+	if optional[M]() && i.trackPointer {
+		i.markPointer()
+	}
 	goto OBJ_KEY
 
 VALUE_ARRAY:
@@ -263,20 +439,40 @@ VALUE_ARRAY:
 		i.arrayIndex = -1
 		if len(i.stack) != 0 &&
 			i.stack[len(i.stack)-1].Type == stackNodeTypeArray {
-			i.arrayIndex = i.stack[len(i.stack)-1].ArrLen
-			i.stack[len(i.stack)-1].ArrLen++
+			i.arrayIndex = i.stack[len(i.stack)-1].Len
+			i.stack[len(i.stack)-1].Len++
 		}
 		if fn(i) {
-			return s, i.getError(ErrorCodeCallback)
+			goto STOP
 		}
 		i.keyIndex = -1
+		if i.skip {
+			if s, err = i.skipValue(); err.IsErr() {
+				return s, err
+			}
+			if optional[M]() && i.endEvents {
+				i.stack = append(i.stack, stackNode{
+					Type:        stackNodeTypeArray,
+					KeyIndex:    ks,
+					KeyIndexEnd: ke,
+					Index:       i.valueIndex,
+					Len:         -1,
+				})
+				if optional[M]() && i.trackPointer {
+					i.markPointer()
+				}
+				if i.invokeEndCallback(fn, len(i.src)-len(s)) {
+					goto STOP
+				}
+			}
+			goto AFTER_VALUE
+		}
 	}
 
-	i.stack = append(i.stack, stackNode{
-		Type:        stackNodeTypeArray,
-		KeyIndex:    ks,
-		KeyIndexEnd: ke,
-	})
+	i.push(stackNodeTypeArray, ks, ke, 0)
+	if optional[M]() && i.trackPointer {
+		i.markPointer()
+	}
 	goto VALUE_OR_ARR_TERM
 
 VALUE_NUMBER:
@@ -288,6 +484,7 @@ VALUE_NUMBER:
 			if s, rc = jsonnum.ReadNumber(s); rc == jsonnum.ReturnCodeErr {
 				return s, getError(ErrorCodeMalformedNumber, i.src, rollback)
 			}
+			i.numberKind = rc
 		}
 		i.valueIndexEnd = len(i.src) - len(s)
 		i.valueType = ValueTypeNumber
@@ -296,11 +493,11 @@ VALUE_NUMBER:
 			i.arrayIndex = -1
 			if len(i.stack) != 0 &&
 				i.stack[len(i.stack)-1].Type == stackNodeTypeArray {
-				i.arrayIndex = i.stack[len(i.stack)-1].ArrLen
-				i.stack[len(i.stack)-1].ArrLen++
+				i.arrayIndex = i.stack[len(i.stack)-1].Len
+				i.stack[len(i.stack)-1].Len++
 			}
 			if fn(i) {
-				return s, i.getError(ErrorCodeCallback)
+				goto STOP
 			}
 			i.keyIndex = -1
 		}
@@ -310,6 +507,7 @@ VALUE_NUMBER:
 VALUE_STRING:
 	s = s[1:]
 	i.valueIndex = len(i.src) - len(s) - 1
+	i.valueEscaped = false
 	for {
 		for ; len(s) > 15; s = s[16:] {
 			if lutStr[s[0]] != 0 {
@@ -384,6 +582,7 @@ VALUE_STRING:
 		}
 		switch s[0] {
 		case '\\':
+			i.valueEscaped = true
 			if len(s) < 2 {
 				s = s[1:]
 				return s, getError(ErrorCodeUnexpectedEOF, i.src, s)
@@ -412,11 +611,11 @@ VALUE_STRING:
 				i.arrayIndex = -1
 				if len(i.stack) != 0 &&
 					i.stack[len(i.stack)-1].Type == stackNodeTypeArray {
-					i.arrayIndex = i.stack[len(i.stack)-1].ArrLen
-					i.stack[len(i.stack)-1].ArrLen++
+					i.arrayIndex = i.stack[len(i.stack)-1].Len
+					i.stack[len(i.stack)-1].Len++
 				}
 				if fn(i) {
-					return s, i.getError(ErrorCodeCallback)
+					goto STOP
 				}
 				i.keyIndex = -1
 			}
@@ -443,11 +642,11 @@ VALUE_NULL:
 		i.arrayIndex = -1
 		if len(i.stack) != 0 &&
 			i.stack[len(i.stack)-1].Type == stackNodeTypeArray {
-			i.arrayIndex = i.stack[len(i.stack)-1].ArrLen
-			i.stack[len(i.stack)-1].ArrLen++
+			i.arrayIndex = i.stack[len(i.stack)-1].Len
+			i.stack[len(i.stack)-1].Len++
 		}
 		if fn(i) {
-			return s, i.getError(ErrorCodeCallback)
+			goto STOP
 		}
 		i.keyIndex = -1
 	}
@@ -467,11 +666,11 @@ VALUE_FALSE:
 		i.arrayIndex = -1
 		if len(i.stack) != 0 &&
 			i.stack[len(i.stack)-1].Type == stackNodeTypeArray {
-			i.arrayIndex = i.stack[len(i.stack)-1].ArrLen
-			i.stack[len(i.stack)-1].ArrLen++
+			i.arrayIndex = i.stack[len(i.stack)-1].Len
+			i.stack[len(i.stack)-1].Len++
 		}
 		if fn(i) {
-			return s, i.getError(ErrorCodeCallback)
+			goto STOP
 		}
 		i.keyIndex = -1
 	}
@@ -491,11 +690,11 @@ VALUE_TRUE:
 		i.arrayIndex = -1
 		if len(i.stack) != 0 &&
 			i.stack[len(i.stack)-1].Type == stackNodeTypeArray {
-			i.arrayIndex = i.stack[len(i.stack)-1].ArrLen
-			i.stack[len(i.stack)-1].ArrLen++
+			i.arrayIndex = i.stack[len(i.stack)-1].Len
+			i.stack[len(i.stack)-1].Len++
 		}
 		if fn(i) {
-			return s, i.getError(ErrorCodeCallback)
+			goto STOP
 		}
 		i.keyIndex = -1
 	}
@@ -624,6 +823,10 @@ OBJ_KEY:
 		case '"':
 			s = s[1:]
 			i.keyIndex, i.keyIndexEnd = i.valueIndex, len(i.src)-len(s)
+			if optional[M]() && i.endEvents {
+				// Members are only counted for end events.
+				i.stack[len(i.stack)-1].Len++
+			}
 			goto AFTER_OBJ_KEY_STRING
 		default:
 			if s[0] < 0x20 {
@@ -676,6 +879,12 @@ VALUE_OR_ARR_TERM:
 	switch s[0] {
 	case ']':
 		s = s[1:]
+		if optional[M]() && i.endEvents {
+			if i.invokeEndCallback(fn, len(i.src)-len(s)) {
+				goto STOP
+			}
+			goto AFTER_VALUE
+		}
 		i.stack = i.stack[:len(i.stack)-1]
 		goto AFTER_VALUE
 	case '{':
@@ -729,6 +938,12 @@ AFTER_VALUE:
 			return s, getError(ErrorCodeUnexpectedToken, i.src, s)
 		}
 		s = s[1:]
+		if optional[M]() && i.endEvents {
+			if i.invokeEndCallback(fn, len(i.src)-len(s)) {
+				goto STOP
+			}
+			goto AFTER_VALUE
+		}
 		i.stack = i.stack[:len(i.stack)-1]
 		i.keyIndex, i.keyIndexEnd = -1, -1
 		goto AFTER_VALUE
@@ -737,6 +952,12 @@ AFTER_VALUE:
 			return s, getError(ErrorCodeUnexpectedToken, i.src, s)
 		}
 		s = s[1:]
+		if optional[M]() && i.endEvents {
+			if i.invokeEndCallback(fn, len(i.src)-len(s)) {
+				goto STOP
+			}
+			goto AFTER_VALUE
+		}
 		i.stack = i.stack[:len(i.stack)-1]
 		i.keyIndex, i.keyIndexEnd = -1, -1
 		goto AFTER_VALUE
@@ -745,4 +966,105 @@ AFTER_VALUE:
 		return s, getError(ErrorCodeIllegalControlChar, i.src, s)
 	}
 	return s, getError(ErrorCodeUnexpectedToken, i.src, s)
+
+STOP: // fn returned true.
+	if optional[M]() && i.pull {
+		// Reader stops scan for every value, hence an error isn't returned
+		// since its construction would dominate the cost of the stop.
+		return s, Error[S]{}
+	}
+	if optional[M]() && i.end {
+		return s, i.getEndError()
+	}
+	return s, i.getError(ErrorCodeCallback)
+}
+
+// skipValue skips the object or array value starting at i.valueIndex
+// without invoking the callback for any of its member or element values.
+// Returns the remainder of i.src and an error if any is encountered.
+func (i *Iterator[S]) skipValue() (S, Error[S]) {
+	i.skip = false
+	if i.valueIndexEnd != -1 {
+		// Already validated by CaptureRaw.
+		return i.src[i.valueIndexEnd:], Error[S]{}
+	}
+	return i.validateValue()
+}
+
+// validateValue validates the object or array value starting at
+// i.valueIndex and returns the remainder of i.src. When scanning with
+// ScanContext the context is checked every contextCheckInterval bytes.
+func (i *Iterator[S]) validateValue() (S, Error[S]) {
+	if i.check == nil {
+		t, err := validate(i.skipStack, i.src[i.valueIndex:])
+		if err.IsErr() {
+			err.Src, err.Index = i.src, err.Index+i.valueIndex
+		}
+		return t, err
+	}
+	var v windowValidator
+	v.reset(i.skipStack, true, 0)
+	for end := i.valueIndex; ; {
+		r := end
+		end = min(len(i.src), end+contextCheckInterval)
+		j, err := validateWindow(&v, i.src[:end], r, 0, end == len(i.src))
+		if err.IsErr() {
+			err.Src = i.src
+			return i.src[j:], err
+		}
+		if v.state == windowDone {
+			return i.src[j:], Error[S]{}
+		}
+		if i.check.check(end) {
+			return i.src[end:], getError(ErrorCodeCanceled, i.src, i.src[end:])
+		}
+	}
+}
+
+// invokeEndCallback pops the top stack frame and invokes fn for the end
+// of the object or array it represents. end is the index in the source
+// immediately after the closing bracket.
+// Returns true if fn returned true, in which case the iterator is left
+// at the end event for Reader.
+func (i *Iterator[S]) invokeEndCallback(
+	fn func(*Iterator[S]) (err bool), end int,
+) bool {
+	i.popEnd(end)
+	if fn(i) {
+		return true
+	}
+	i.end, i.len = false, -1
+	i.keyIndex, i.keyIndexEnd = -1, -1
+	return false
+}
+
+// popEnd pops the top stack frame and sets up the iterator for the end event
+// of the object or array it represents. end is the index in the source
+// immediately after the closing bracket.
+func (i *Iterator[S]) popEnd(end int) {
+	n := i.stack[len(i.stack)-1]
+	i.stack = i.stack[:len(i.stack)-1]
+
+	i.valueType = ValueTypeObject
+	if n.Type == stackNodeTypeArray {
+		i.valueType = ValueTypeArray
+	}
+	i.valueIndex, i.valueIndexEnd = n.Index, end
+	i.keyIndex, i.keyIndexEnd = n.KeyIndex, n.KeyIndexEnd
+	i.arrayIndex = -1
+	if len(i.stack) != 0 &&
+		i.stack[len(i.stack)-1].Type == stackNodeTypeArray {
+		i.arrayIndex = i.stack[len(i.stack)-1].Len - 1
+	}
+	i.end, i.len = true, n.Len
+}
+
+// getEndError returns ErrorCodeCallback pointing at the closing bracket
+// of the object or array of the current end event.
+func (i *Iterator[S]) getEndError() Error[S] {
+	return Error[S]{
+		Code:  ErrorCodeCallback,
+		Src:   i.src,
+		Index: i.valueIndexEnd - 1,
+	}
 }
@@ -0,0 +1,37 @@
+package jscan
+
+// ScanSegments is similar to Scan but treats the segments, such as
+// net.Buffers, as one logical input without concatenating them.
+// ValueIndex, ValueIndexEnd, KeyIndex and KeyIndexEnd and the indexes of
+// errors refer to the logical input. Key and Value of the current value
+// remain valid during the callback, only keys and values crossing
+// segment boundaries are copied into a scratch buffer.
+// The Src of a returned error is either the segment the error was
+// encountered in or the scratch buffer if the error crosses segments.
+//
+// This function will take a parser instance from a global pool.
+//
+// WARNING: Don't use or alias *Iterator[[]byte] after fn returns!
+func ScanSegments(
+	segments [][]byte, fn func(*Iterator[[]byte]) (err bool),
+) Error[[]byte] {
+	p := pushParserPool.Get().(*PushParser)
+	defer func() {
+		p.release()
+		pushParserPool.Put(p)
+	}()
+	p.restart(fn)
+	for _, x := range segments {
+		if p.feed(x); p.s.err.IsErr() {
+			return p.s.err
+		}
+	}
+	return p.close()
+}
+
+// ValidateSegments is similar to Validate but treats the segments, such as
+// net.Buffers, as one logical input without concatenating them.
+// See ScanSegments for more details.
+func ValidateSegments(segments [][]byte) Error[[]byte] {
+	return ScanSegments(segments, func(*Iterator[[]byte]) bool { return false })
+}
@@ -0,0 +1,85 @@
+package jscan_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// segment splits s into segments of n bytes with an empty segment
+// between each of them.
+func segment(s string, n int) [][]byte {
+	var segments [][]byte
+	for len(s) > n {
+		segments = append(segments, []byte(s[:n]), []byte{})
+		s = s[n:]
+	}
+	return append(segments, []byte(s))
+}
+
+// asError returns e as error or nil if e isn't an error.
+func asError(e jscan.Error[[]byte]) error {
+	if !e.IsErr() {
+		return nil
+	}
+	return e
+}
+
+func TestScanSegments(t *testing.T) {
+	for _, input := range streamTestInputs {
+		t.Run(input, func(t *testing.T) {
+			var expect []streamRecord
+			expectErr := jscan.NewParserWithOptions[string](
+				64, jscan.ParserOptions{TrackPointer: true},
+			).Scan(input, func(i *jscan.Iterator[string]) (err bool) {
+				expect = append(expect, newStreamRecord(i))
+				return false
+			})
+
+			for _, n := range []int{1, 2, 3, 5, 16, len(input) + 1} {
+				var actual []streamRecord
+				err := jscan.ScanSegments(segment(input, n),
+					func(i *jscan.Iterator[[]byte]) (err bool) {
+						actual = append(actual, newStreamRecord(i))
+						return false
+					})
+				require.Equal(t, expect, actual, "segment size: %d", n)
+				requireStreamErr(t, expectErr, asError(err))
+
+				err = jscan.ValidateSegments(segment(input, n))
+				requireStreamErr(t, expectErr, asError(err))
+			}
+		})
+	}
+}
+
+func TestScanSegmentsNoCopy(t *testing.T) {
+	segments := net.Buffers{
+		[]byte(`{"a":"first segment",`),
+		[]byte(`"b":"second segment"}`),
+	}
+	var values [][]byte
+	err := jscan.ScanSegments(segments, func(i *jscan.Iterator[[]byte]) (err bool) {
+		if i.ValueType() == jscan.ValueTypeString {
+			values = append(values, i.Value())
+		}
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error: %v", err)
+	require.Len(t, values, 2)
+	// Values contained within a segment must refer to the segment itself.
+	require.Equal(t, &segments[0][5], &values[0][0])
+	require.Equal(t, &segments[1][4], &values[1][0])
+}
+
+func TestScanSegmentsErr(t *testing.T) {
+	segments := [][]byte{[]byte(`[1,2`), []byte(`,]`)}
+	err := jscan.ScanSegments(segments, func(*jscan.Iterator[[]byte]) bool {
+		return false
+	})
+	require.Equal(t, jscan.ErrorCodeUnexpectedToken, err.Code)
+	require.Equal(t, 5, err.Index)
+	require.Equal(t, `error at index 5 (']'): unexpected token`, err.Error())
+}
@@ -0,0 +1,485 @@
+package jscan
+
+import (
+	"io"
+
+	"github.com/romshark/jscan/v2/internal/strfind"
+)
+
+// streamScanner runs scan over a window of a stream which is refilled
+// as the stream is read. Whenever the window ends in the middle of a value
+// scanning resumes from the last checkpoint (see (*Iterator).Checkpoint)
+// once more of the stream is available, hence only the part of the stream
+// following the last value must be retained.
+type streamScanner struct {
+	i Iterator[[]byte]
+
+	// fn is the callback of the user and
+	// callback is scanCallback passed to scan.
+	fn, callback func(*Iterator[[]byte]) (err bool)
+
+	// one stops the scanner once the value was read entirely.
+	one bool
+	// eof is true once the end of the stream is reached.
+	eof bool
+	// done is true once the value was read entirely.
+	done bool
+	// more is set when the window ends right after a number,
+	// which may continue in the following part of the stream.
+	more bool
+	// inString is set when the window ends within a string,
+	// which can't end before the following quotation mark.
+	inString bool
+
+	// state and resume are the state and the index in the stream of
+	// the last checkpoint, depth and len are the depth of the stack and
+	// the Len of the object or array on top of it at the checkpoint.
+	state      checkpointState
+	resume     int
+	depth, len int
+
+	// fixed is the number of objects and arrays on the stack
+	// the indexes of which are relative to the stream, see fix.
+	fixed int
+
+	// skip is the level of the object or array the children
+	// of which are skipped or -1 if none.
+	skip int
+
+	// remap is set while scanning a copy of the window the part of which
+	// starting at index remapOffset of the stream is also available in remap.
+	remap       []byte
+	remapOffset int
+
+	err Error[[]byte]
+}
+
+// init initializes s.
+func (s *streamScanner) init() {
+	s.i = Iterator[[]byte]{
+		stack: make([]stackNode, 0, DefaultStackSizeIterator),
+		// End events make every change of the stack a checkpoint.
+		endEvents: true,
+		stream:    true,
+	}
+	s.callback = s.scanCallback
+}
+
+// begin prepares s for reading the next value.
+func (s *streamScanner) begin(fn func(*Iterator[[]byte]) (err bool), one bool) {
+	s.fn, s.one, s.done, s.more = fn, one, false, false
+	s.state, s.depth, s.len, s.fixed, s.skip = 0, 0, 0, 0, -1
+	reset(&s.i)
+	s.i.keys = s.i.keys[:0]
+}
+
+// run scans the window w, which starts at index offset of the stream,
+// from w[r] on and returns the index in w scanning must resume from
+// once more of the stream is available.
+func (s *streamScanner) run(w []byte, offset, r int) int {
+	i := &s.i
+	for !s.err.IsErr() {
+		// All states start with optional whitespace.
+		t, illegalChar := strfind.EndOfWhitespaceSeq(w[r:])
+		r, s.resume = len(w)-len(t), offset+len(w)-len(t)
+		if s.done {
+			switch {
+			case illegalChar:
+				s.fail(ErrorCodeIllegalControlChar, w, offset, r)
+			case len(t) > 0:
+				s.fail(ErrorCodeUnexpectedToken, w, offset, r)
+			}
+			return r
+		}
+		if len(t) == 0 && (!s.eof || (s.one && s.state == 0)) {
+			return r
+		}
+
+		if s.state == 0 {
+			i.src, i.offset = w[r:], offset+r
+		} else {
+			i.src, i.offset = w, offset
+			i.resumeState, i.resumeOffset = s.state, r
+		}
+		t, err := scan(i, s.callback)
+		if !err.IsErr() {
+			s.done, r = true, len(w)-len(t)
+			if s.one {
+				s.resume = offset + r
+				return r
+			}
+			continue
+		}
+		if s.more || (!s.eof && incomplete(err.Code, t)) {
+			// scan sets valueIndex at the start of strings and keys,
+			// keyIndex equals it once a key was read entirely.
+			s.inString = err.Code == ErrorCodeUnexpectedEOF &&
+				i.offset+i.valueIndex >= s.resume &&
+				i.valueIndex < len(i.src) && i.src[i.valueIndex] == '"' &&
+				i.keyIndex != i.valueIndex
+			s.more = false
+			s.restore()
+			return s.resume - offset
+		}
+		err.Index += i.offset
+		err.offset = i.offset
+		s.err = err
+	}
+	return r
+}
+
+// fail records an error of code c at w[r] given that w starts
+// at index offset of the stream.
+func (s *streamScanner) fail(c ErrorCode, w []byte, offset, r int) {
+	s.err = Error[[]byte]{Src: w, Index: offset + r, Code: c, offset: offset}
+}
+
+// incomplete returns true if the error of code c encountered at t
+// may be caused by the end of the window rather than by invalid JSON,
+// such as for truncated literals and escape sequences.
+func incomplete(c ErrorCode, t []byte) bool {
+	switch c {
+	case ErrorCodeUnexpectedEOF:
+		return true
+	case ErrorCodeMalformedNumber:
+		return len(t) == 0
+	case ErrorCodeInvalidEscape:
+		return len(t) < len(`\u0000`)
+	case ErrorCodeUnexpectedToken:
+		for _, l := range [...]string{"true", "false", "null"} {
+			if len(t) < len(l) && l[:len(t)] == string(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scanCallback is the callback passed to scan. It records a checkpoint
+// for every value and end of an object or array and calls fn for
+// every value unless skipped.
+func (s *streamScanner) scanCallback(i *Iterator[[]byte]) (err bool) {
+	s.fix()
+	if i.end {
+		if len(i.stack) == s.skip {
+			s.skip = -1
+		}
+		s.checkpoint(checkpointStateAfterValue, i.valueIndexEnd, 0)
+		return false
+	}
+	if i.valueType == ValueTypeNumber && !s.eof &&
+		i.valueIndexEnd == len(i.src) {
+		// The number may continue in the following part of the stream.
+		s.more = true
+		return true
+	}
+	if s.skip == -1 {
+		if s.deliver(i) {
+			return true
+		}
+		if i.skip {
+			// Skip the children without calling fn instead of validating
+			// them within the window like (*Iterator).skipValue.
+			i.skip, s.skip = false, len(i.stack)
+		}
+	}
+	switch i.valueType {
+	case ValueTypeObject:
+		s.checkpoint(checkpointStateObject, i.valueIndex+1, 1)
+	case ValueTypeArray:
+		s.checkpoint(checkpointStateArray, i.valueIndex+1, 1)
+	default:
+		s.checkpoint(checkpointStateAfterValue, i.valueIndexEnd, 0)
+	}
+	return false
+}
+
+// deliver calls fn for the current value. If the window is a copy and
+// both the value and its key are also available in remap,
+// Key and Value refer to remap instead.
+func (s *streamScanner) deliver(i *Iterator[[]byte]) (err bool) {
+	if s.remap == nil {
+		return s.fn(i)
+	}
+	shift, first := s.remapOffset-i.offset, i.valueIndex
+	if i.keyIndex != -1 {
+		first = i.keyIndex
+	}
+	if first < shift {
+		return s.fn(i)
+	}
+	s.saveKeys(s.remapOffset)
+	src, offset := i.src, i.offset
+	vs, ve, ks, ke := i.valueIndex, i.valueIndexEnd, i.keyIndex, i.keyIndexEnd
+	i.src, i.offset, i.valueIndex = s.remap, s.remapOffset, vs-shift
+	if ve != -1 {
+		i.valueIndexEnd = ve - shift
+	}
+	if ks != -1 {
+		i.keyIndex, i.keyIndexEnd = ks-shift, ke-shift
+	}
+	err = s.fn(i)
+	i.src, i.offset = src, offset
+	i.valueIndex, i.valueIndexEnd, i.keyIndex, i.keyIndexEnd = vs, ve, ks, ke
+	return err
+}
+
+// checkpoint records a checkpoint in the given state resuming at
+// i.src[index]. push is 1 if the current object or array is about
+// to be pushed onto the stack, otherwise 0.
+func (s *streamScanner) checkpoint(state checkpointState, index, push int) {
+	i := &s.i
+	s.state, s.resume = state, i.offset+index
+	s.depth, s.len = len(i.stack)+push, 0
+	if push == 0 && s.depth > 0 {
+		s.len = i.stack[s.depth-1].Len
+	}
+}
+
+// restore restores the stack as it was at the last checkpoint.
+// Only the object or array on top of the stack may have changed since,
+// by counting a member, or been pushed.
+func (s *streamScanner) restore() {
+	i := &s.i
+	i.stack = i.stack[:s.depth]
+	s.fix()
+	if s.depth > 0 {
+		i.stack[s.depth-1].Len = s.len
+	}
+	i.keyIndex, i.keyIndexEnd = -1, -1
+	i.skip = false
+}
+
+// fix makes the indexes of the objects and arrays pushed onto the stack
+// by scan, which are relative to i.src, relative to the stream instead.
+func (s *streamScanner) fix() {
+	i := &s.i
+	s.fixed = min(s.fixed, len(i.stack))
+	for ; s.fixed < len(i.stack); s.fixed++ {
+		n := &i.stack[s.fixed]
+		n.Index += i.offset
+		if n.KeyIndex != -1 {
+			n.KeyIndex += i.offset
+			n.KeyIndexEnd += i.offset
+		}
+	}
+}
+
+// saveKeys copies the keys of the objects and arrays on the stack
+// that precede index of the stream, which is about to be discarded.
+func (s *streamScanner) saveKeys(index int) {
+	i := &s.i
+	s.fix()
+	for j := range i.stack {
+		n := &i.stack[j]
+		if n.KeyIndex >= index {
+			// The keys of all following levels are located after it.
+			return
+		}
+		if n.KeyIndex == -1 ||
+			(j < len(i.keys) && i.keys[j].index == n.KeyIndex) {
+			continue
+		}
+		for l := len(i.keys); l <= j; l++ {
+			if l < cap(i.keys) {
+				i.keys = i.keys[:l+1]
+				i.keys[l].index = -1
+			} else {
+				i.keys = append(i.keys, savedKey{index: -1})
+			}
+		}
+		k := &i.keys[j]
+		k.index = n.KeyIndex
+		k.b = append(k.b[:0], i.src[n.KeyIndex-i.offset:n.KeyIndexEnd-i.offset]...)
+	}
+}
+
+// DefaultStreamBufferSize is the default buffer size of stream parsers.
+const DefaultStreamBufferSize = 64 * 1024
+
+// StreamParser scans JSON read from an io.Reader using a buffer
+// instead of the entire input, see NewStreamParser.
+type StreamParser struct {
+	s   streamScanner
+	r   io.Reader
+	buf []byte
+
+	// start is the index in buf scanning resumes from,
+	// buf[:end] starts at index offset of the stream.
+	start, end, offset int
+
+	readErr error
+}
+
+// NewStreamParser creates a new parser reading from r into a buffer of
+// bufSize bytes. DefaultStreamBufferSize is used if bufSize is less than 1.
+// The buffer only holds the part of the stream following the last value
+// and grows if a single key and value don't fit into it.
+//
+// The callback model is the same as with (*Parser).Scan except that
+// ValueIndex, ValueIndexEnd, KeyIndex and KeyIndexEnd, the indexes of
+// Frame and ScanStack, checkpoints and the indexes of errors refer to
+// the stream offset and the Src of errors only contains the part of the stream buffered
+// at the time the error was encountered. Key and Value of the current
+// value and the keys of all objects and arrays on the stack remain valid
+// during the callback.
+// Since objects and arrays aren't buffered, CaptureRaw returns "" for them
+// but still skips their children just like ActionSkipChildren does
+// (see (*StreamParser).ScanAction).
+func NewStreamParser(r io.Reader, bufSize int) *StreamParser {
+	if bufSize < 1 {
+		bufSize = DefaultStreamBufferSize
+	}
+	p := &StreamParser{buf: make([]byte, bufSize)}
+	p.s.init()
+	p.Reset(r)
+	return p
+}
+
+// Reset resets p to read from r reusing its buffers.
+func (p *StreamParser) Reset(r io.Reader) {
+	p.r, p.readErr = r, nil
+	p.start, p.end, p.offset = 0, 0, 0
+	p.s.eof, p.s.err = false, Error[[]byte]{}
+}
+
+// Scan reads the entire stream and calls fn for every encountered value
+// including objects and arrays. Returns an error of type Error[[]byte]
+// if the stream is invalid JSON or contains anything other than whitespace
+// after the value, otherwise returns the error returned by the reader if any.
+//
+// WARNING: Don't use or alias *Iterator[[]byte] after fn returns!
+func (p *StreamParser) Scan(fn func(*Iterator[[]byte]) (err bool)) error {
+	p.s.begin(fn, false)
+	return p.scan()
+}
+
+// ScanOne is similar to Scan but stops after reading one value such that
+// subsequent calls to ScanOne read the following values of the stream,
+// which is useful for streams of concatenated or newline delimited values.
+// Returns io.EOF if the stream contains only whitespace.
+//
+// WARNING: Don't use or alias *Iterator[[]byte] after fn returns!
+func (p *StreamParser) ScanOne(fn func(*Iterator[[]byte]) (err bool)) error {
+	p.s.begin(fn, true)
+	return p.scan()
+}
+
+// ScanAction is similar to Scan except that fn returns an Action.
+// See ScanAction for more details.
+//
+// WARNING: Don't use or alias *Iterator[[]byte] after fn returns!
+func (p *StreamParser) ScanAction(fn func(*Iterator[[]byte]) Action) error {
+	return p.Scan(actionCallback(fn))
+}
+
+// ScanOneAction is similar to ScanOne except that fn returns an Action.
+// See ScanAction for more details.
+//
+// WARNING: Don't use or alias *Iterator[[]byte] after fn returns!
+func (p *StreamParser) ScanOneAction(fn func(*Iterator[[]byte]) Action) error {
+	return p.ScanOne(actionCallback(fn))
+}
+
+// scan reads the stream until either the value was read entirely in one
+// mode, the end of the stream is reached or an error is encountered.
+// Returns io.EOF in one mode if the stream contains only whitespace.
+func (p *StreamParser) scan() error {
+	s := &p.s
+	for {
+		p.start = s.run(p.buf[:p.end], p.offset, p.start)
+		if s.err.IsErr() {
+			return s.err
+		}
+		if s.done && s.one {
+			return nil
+		}
+		if p.readErr != nil {
+			if p.readErr != io.EOF {
+				return p.readErr
+			}
+			if !s.done {
+				return io.EOF
+			}
+			return nil
+		}
+		p.read()
+	}
+}
+
+// read reads the following part of the stream into the buffer making room
+// for it by either discarding the part preceding start or growing the buffer.
+func (p *StreamParser) read() {
+	if p.end == len(p.buf) {
+		if p.start < len(p.buf)/2 || p.start == 0 {
+			p.buf = append(p.buf, make([]byte, len(p.buf))...)
+		} else {
+			p.s.saveKeys(p.offset + p.start)
+			p.end = copy(p.buf, p.buf[p.start:p.end])
+			p.offset += p.start
+			p.start = 0
+		}
+	}
+	n, err := p.r.Read(p.buf[p.end:])
+	p.end += n
+	if err != nil {
+		p.readErr, p.s.eof = err, err == io.EOF
+	}
+}
+
+// ValidateReader is similar to (*Validator).Validate but reads the input
+// from r into a buffer of DefaultStreamBufferSize bytes, which is validated
+// and then reused for the next read. Hence the memory usage is independent
+// of the size of the input and of the size of its values, except for
+// validators with a number type constraint, which grow the buffer
+// if a single number doesn't fit into half of it.
+// The index of a returned error is relative to the start of the stream
+// and Src only contains the part of the stream buffered at the time
+// the error was encountered. Returns ErrorCodeRead if r returns an error
+// other than io.EOF before the input is found to be invalid.
+func (v *Validator[S]) ValidateReader(r io.Reader) Error[S] {
+	if v.buf == nil {
+		v.buf = make([]byte, DefaultStreamBufferSize)
+	}
+	w, buf := &v.window, v.buf
+	w.reset(v.stack, false, v.numbers)
+	for offset, end := 0, 0; ; {
+		if end == len(buf) {
+			// Keep the escape sequence, number or literal being read
+			// if it's short, such that errors can refer to it,
+			// and drop the rest of the buffer.
+			keep := end
+			if start, ok := w.pending(); ok {
+				switch start -= offset; {
+				case end-start <= len(buf)/2:
+					keep = start
+				case v.numbers != 0 && w.state == windowNumber:
+					// The entire number is required to check it.
+					if keep = start; keep < len(buf)/2 {
+						buf = append(buf, make([]byte, len(buf))...)
+					}
+				}
+			}
+			end = copy(buf, buf[keep:end])
+			offset += keep
+		}
+		n, err := r.Read(buf[end:])
+		eof := err == io.EOF
+		if n > 0 || eof {
+			end += n
+			_, e := validateWindow(w, buf[:end], end-n, offset, eof)
+			if e.IsErr() {
+				return Error[S]{
+					Src: S(e.Src), Index: e.Index, Code: e.Code, offset: e.offset,
+				}
+			}
+		}
+		if eof {
+			return Error[S]{}
+		}
+		if err != nil {
+			n := offset + end
+			return Error[S]{Index: n, Code: ErrorCodeRead, offset: n, err: err}
+		}
+	}
+}
@@ -0,0 +1,543 @@
+package jscan_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var streamTestInputs = append([]string{
+	`"ä😀 \\\/\b\f\n\r\t"`,
+	`[0,-0,1,-12,0.5,-1.25e-3,1E+10,1e5,123456789012345678901234567890]`,
+	`{"key with \"escapes\"":"value A","":{"":[]}}`,
+	` [ [ [ ] ] , { } , "" , 0 ] `,
+	`["` + strings.Repeat("long string ", 100) + `"]`,
+	// Invalid inputs
+	`01`,
+	`[1-2]`,
+	`[1.]`,
+	`[1e]`,
+	`[1e+]`,
+	`-`,
+	`1.`,
+	`"\u12"`,
+	`"\u12`,
+	`"\`,
+	`"abc`,
+	`nul`,
+	`nulx`,
+	`[truex]`,
+	`{"a":1,}`,
+	`{"a" 1}`,
+	`{"a":1 "b":2}`,
+	`[1 2]`,
+	`]`,
+	`}`,
+	"[\x00]",
+	"\x00",
+	`[1]]`,
+	`{} {}`,
+	`"\x"`,
+	"{\x01",
+	"{ \x01",
+	"[ \x01",
+	`{"a":{`,
+	`{"a":[`,
+	"1\x01",
+}, readerTestInputs...)
+
+// streamRecord is a Record with additional fields of the iterator.
+type streamRecord struct {
+	Record
+	ValueIndex, ValueIndexEnd int
+	KeyIndex, KeyIndexEnd     int
+	Escaped                   bool
+	NumberKind                jscan.NumberKind
+}
+
+func newStreamRecord[S ~string | ~[]byte](i *jscan.Iterator[S]) streamRecord {
+	r := streamRecord{
+		Record:        readerRecord(i),
+		ValueIndex:    i.ValueIndex(),
+		ValueIndexEnd: i.ValueIndexEnd(),
+		KeyIndex:      i.KeyIndex(),
+		KeyIndexEnd:   -1,
+		Escaped:       i.ValueHasEscapes(),
+	}
+	if r.KeyIndex != -1 {
+		r.KeyIndexEnd = i.KeyIndexEnd()
+	}
+	if i.ValueType() == jscan.ValueTypeNumber {
+		r.NumberKind = i.NumberKind()
+	}
+	return r
+}
+
+func TestStreamParser(t *testing.T) {
+	for _, input := range streamTestInputs {
+		t.Run(input, func(t *testing.T) {
+			var expect []streamRecord
+			expectErr := jscan.NewParserWithOptions[string](
+				64, jscan.ParserOptions{TrackPointer: true},
+			).Scan(input, func(i *jscan.Iterator[string]) (err bool) {
+				expect = append(expect, newStreamRecord(i))
+				return false
+			})
+
+			for _, bufSize := range []int{1, 2, 3, 5, 16, 0} {
+				var actual []streamRecord
+				p := jscan.NewStreamParser(strings.NewReader(input), bufSize)
+				err := p.Scan(func(i *jscan.Iterator[[]byte]) (err bool) {
+					actual = append(actual, newStreamRecord(i))
+					return false
+				})
+				require.Equal(t, expect, actual, "bufSize: %d", bufSize)
+				requireStreamErr(t, expectErr, err)
+			}
+		})
+	}
+}
+
+func FuzzStreamParser(f *testing.F) {
+	for _, s := range streamTestInputs {
+		f.Add(s, uint8(3))
+	}
+	f.Fuzz(func(t *testing.T, data string, bufSize uint8) {
+		var expect []streamRecord
+		expectErr := jscan.NewParserWithOptions[string](
+			64, jscan.ParserOptions{TrackPointer: true},
+		).Scan(data, func(i *jscan.Iterator[string]) (err bool) {
+			expect = append(expect, newStreamRecord(i))
+			return false
+		})
+		var actual []streamRecord
+		p := jscan.NewStreamParser(strings.NewReader(data), int(bufSize%32)+1)
+		err := p.Scan(func(i *jscan.Iterator[[]byte]) (err bool) {
+			actual = append(actual, newStreamRecord(i))
+			return false
+		})
+		require.Equal(t, expect, actual)
+		requireStreamErr(t, expectErr, err)
+	})
+}
+
+// requireStreamErr requires err to be equivalent to expect.
+func requireStreamErr(t *testing.T, expect jscan.Error[string], err error) {
+	t.Helper()
+	if !expect.IsErr() {
+		require.NoError(t, err)
+		return
+	}
+	var e jscan.Error[[]byte]
+	require.True(t, errors.As(err, &e), "unexpected error: %v", err)
+	require.Equal(t, expect.Code, e.Code, "error: %v; expected: %v", e, expect)
+	require.Equal(t, expect.Index, e.Index, "error: %v; expected: %v", e, expect)
+	// Even when the source is no longer entirely buffered,
+	// the message must refer to the same index.
+	require.Contains(t, e.Error(), strings.SplitN(expect.Error(), " (", 2)[0])
+}
+
+func TestStreamParserScanOne(t *testing.T) {
+	const input = ` {"a":1} [2,3] "4"5 6` + "\n" + `null {"b":` + "\t"
+	p := jscan.NewStreamParser(iotest.OneByteReader(strings.NewReader(input)), 4)
+	var values []string
+	for {
+		var v []string
+		err := p.ScanOne(func(i *jscan.Iterator[[]byte]) (err bool) {
+			if i.Level() == 0 {
+				v = append(v, i.ValueType().String())
+			}
+			return false
+		})
+		if err != nil {
+			var e jscan.Error[[]byte]
+			require.True(t, errors.As(err, &e), "unexpected error: %v", err)
+			require.Equal(t, jscan.ErrorCodeUnexpectedEOF, e.Code)
+			require.Equal(t, len(input), e.Index)
+			break
+		}
+		values = append(values, v...)
+	}
+	require.Equal(t, []string{
+		"object", "array", "string", "number", "number", "null",
+	}, values)
+
+	p.Reset(strings.NewReader(" 1 \n"))
+	require.NoError(t, p.ScanOne(func(*jscan.Iterator[[]byte]) bool { return false }))
+	require.Equal(t, io.EOF,
+		p.ScanOne(func(*jscan.Iterator[[]byte]) bool { return false }))
+}
+
+func TestStreamParserCallbackErr(t *testing.T) {
+	p := jscan.NewStreamParser(strings.NewReader(`[1, {"key": "value"}]`), 3)
+	err := p.Scan(func(i *jscan.Iterator[[]byte]) (err bool) {
+		return string(i.Key()) == `"key"`
+	})
+	var e jscan.Error[[]byte]
+	require.True(t, errors.As(err, &e))
+	require.Equal(t, jscan.ErrorCodeCallback, e.Code)
+	require.Equal(t, 12, e.Index)
+}
+
+func TestStreamParserReadErr(t *testing.T) {
+	errRead := errors.New("read error")
+	p := jscan.NewStreamParser(
+		io.MultiReader(strings.NewReader(`[1,2`), iotest.ErrReader(errRead)), 2,
+	)
+	var values []string
+	err := p.Scan(func(i *jscan.Iterator[[]byte]) (err bool) {
+		values = append(values, string(i.Value()))
+		return false
+	})
+	require.ErrorIs(t, err, errRead)
+	require.Equal(t, []string{"", "1"}, values)
+}
+
+func TestStreamParserBoundedMemory(t *testing.T) {
+	// 1000 elements each of which is larger than the buffer.
+	r := io.MultiReader(
+		strings.NewReader(`[`),
+		strings.NewReader(strings.Repeat(
+			`{"k":"`+strings.Repeat("x", 100)+`"},`, 999,
+		)),
+		strings.NewReader(`{"k":"`+strings.Repeat("x", 100)+`"}]`),
+	)
+	p := jscan.NewStreamParser(r, 16)
+	n, offset := 0, 0
+	err := p.Scan(func(i *jscan.Iterator[[]byte]) (err bool) {
+		if i.ValueType() == jscan.ValueTypeString {
+			require.Equal(t, `"`+strings.Repeat("x", 100)+`"`, string(i.Value()))
+			require.Equal(t, `"k"`, string(i.Key()))
+			require.Greater(t, i.ValueIndex(), offset)
+			offset = i.ValueIndex()
+			n++
+		}
+		return false
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1000, n)
+}
+
+func TestValidatorValidateReader(t *testing.T) {
+	for _, input := range streamTestInputs {
+		t.Run(input, func(t *testing.T) {
+			expect := jscan.Validate(input)
+			v := jscan.NewValidator[string](64)
+			for _, r := range []io.Reader{
+				strings.NewReader(input),
+				iotest.OneByteReader(strings.NewReader(input)),
+				iotest.HalfReader(strings.NewReader(input)),
+			} {
+				err := v.ValidateReader(r)
+				require.Equal(t, expect.Code, err.Code)
+				require.Equal(t, expect.Index, err.Index)
+				if expect.IsErr() {
+					require.Equal(t, expect.Error(), err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestValidatorValidateReaderNumbers(t *testing.T) {
+	v := jscan.NewValidatorWithOptions[[]byte](64, jscan.ValidatorOptions{
+		Numbers: jscan.NumberTypeInt64,
+	})
+	err := v.ValidateReader(iotest.OneByteReader(
+		strings.NewReader(`{"a":[-9223372036854775808, 9223372036854775807]}`),
+	))
+	require.False(t, err.IsErr(), "unexpected error: %v", err)
+	err = v.ValidateReader(iotest.OneByteReader(
+		strings.NewReader(`{"a":[1, 9223372036854775808]}`),
+	))
+	require.Equal(t, jscan.ErrorCodeNumberNotRepresentable, err.Code)
+	require.Equal(t, 9, err.Index)
+}
+
+func TestValidatorValidateReaderReadErr(t *testing.T) {
+	errRead := errors.New("read error")
+	v := jscan.NewValidator[[]byte](64)
+	err := v.ValidateReader(
+		io.MultiReader(strings.NewReader(`[1,2`), iotest.ErrReader(errRead)),
+	)
+	require.Equal(t, jscan.ErrorCodeRead, err.Code)
+	require.Equal(t, 4, err.Index)
+	require.ErrorIs(t, err, errRead)
+	require.Equal(t, "error at index 4: read error: read error", err.Error())
+
+	// Syntax errors take precedence over subsequent read errors.
+	err = v.ValidateReader(
+		io.MultiReader(strings.NewReader(`[1,,`), iotest.ErrReader(errRead)),
+	)
+	require.Equal(t, jscan.ErrorCodeUnexpectedToken, err.Code)
+	require.Equal(t, 3, err.Index)
+	require.Equal(t, "error at index 3 (','): unexpected token", err.Error())
+}
+
+func TestValidatorValidateReaderConstantMemory(t *testing.T) {
+	element := `{"key":"` + strings.Repeat("x", 1024) + `","n":[1,2.5,null]},`
+	input := func(n int) io.Reader {
+		return io.MultiReader(
+			strings.NewReader(`[`),
+			strings.NewReader(strings.Repeat(element, n)),
+			strings.NewReader(`0]`),
+		)
+	}
+	v := jscan.NewValidator[[]byte](64)
+	require.False(t, v.ValidateReader(input(1)).IsErr())
+
+	inputs := make([]io.Reader, 0, 10)
+	for i := 0; i < cap(inputs); i++ {
+		inputs = append(inputs, input(4096))
+	}
+	allocs := testing.AllocsPerRun(len(inputs)-1, func() {
+		r := inputs[0]
+		inputs = inputs[1:]
+		if err := v.ValidateReader(r); err.IsErr() {
+			panic(err)
+		}
+	})
+	require.LessOrEqual(t, allocs, 1.0)
+}
+
+func TestValidatorValidateReaderLargeValues(t *testing.T) {
+	const size = 1024 * 1024 // Larger than the buffer.
+	v := jscan.NewValidator[[]byte](64)
+	require.False(t, v.ValidateReader(strings.NewReader(`[]`)).IsErr())
+
+	for _, td := range []struct {
+		name, input string
+	}{
+		{"string", `["` + strings.Repeat("a", size) + `"]`},
+		{"escaped", `{"` + strings.Repeat(`\u00e4`, size/6) + `":0}`},
+		{"number", `[1` + strings.Repeat("0", size) + `.5e3]`},
+		{"whitespace", `[` + strings.Repeat(" ", size) + `]`},
+	} {
+		t.Run(td.name, func(t *testing.T) {
+			r := strings.NewReader(td.input)
+			allocs := testing.AllocsPerRun(1, func() {
+				r.Reset(td.input)
+				if err := v.ValidateReader(r); err.IsErr() {
+					panic(err)
+				}
+			})
+			require.Zero(t, allocs)
+
+			// Errors at the end of the value.
+			input := td.input[:len(td.input)-1] + "\x00"
+			expect := jscan.Validate(input)
+			err := v.ValidateReader(strings.NewReader(input))
+			require.Equal(t, expect.Code, err.Code)
+			require.Equal(t, expect.Index, err.Index)
+			require.Equal(t, expect.Error(), err.Error())
+		})
+	}
+}
+
+func TestValidatorValidateReaderAcrossBuffers(t *testing.T) {
+	// Place the values right before the end of the buffer
+	// so they continue after the buffer is refilled.
+	v := jscan.NewValidatorWithOptions[string](64, jscan.ValidatorOptions{
+		Numbers: jscan.NumberTypeInt64,
+	})
+	for _, value := range []string{
+		`"\u12x4"`, `"ab\`, `"\u00e4\n"`, `-1.e5`, `1.5e+`, `-12345`,
+		`1.5`, `truex`, `nulL`, `false`, `"a` + "\x01", `{"a" 1}`, `[1}`,
+	} {
+		for shift := 1; shift < len(value); shift++ {
+			input := "[" +
+				strings.Repeat(" ", jscan.DefaultStreamBufferSize-1-shift) +
+				value + "]"
+			expect := v.Validate(input)
+			for _, r := range []io.Reader{
+				strings.NewReader(input),
+				iotest.HalfReader(strings.NewReader(input)),
+			} {
+				err := v.ValidateReader(r)
+				require.Equal(t, expect.Code, err.Code, "%q %d", value, shift)
+				require.Equal(t, expect.Index, err.Index, "%q %d", value, shift)
+				if expect.IsErr() {
+					require.Equal(t, expect.Error(), err.Error())
+				}
+			}
+		}
+	}
+
+	// With a number type constraint numbers longer than the buffer
+	// are buffered entirely.
+	v = jscan.NewValidatorWithOptions[string](64, jscan.ValidatorOptions{
+		Numbers: jscan.NumberTypeFloat64,
+	})
+	zeros := strings.Repeat("0", jscan.DefaultStreamBufferSize*2)
+	err := v.ValidateReader(strings.NewReader(`[1.` + zeros + `]`))
+	require.False(t, err.IsErr(), "unexpected error: %v", err)
+	err = v.ValidateReader(strings.NewReader(`[0,1` + zeros + `1]`))
+	require.Equal(t, jscan.ErrorCodeNumberNotRepresentable, err.Code)
+	require.Equal(t, 3, err.Index)
+}
+
+// stackRecord records the paths and the stack of the current value.
+type stackRecord struct {
+	NormalizedPath, DotPath string
+	Match                   int
+	Stack                   []string
+	Frames                  []string
+}
+
+func newStackRecord[S ~string | ~[]byte](
+	i *jscan.Iterator[S], p *jscan.Patterns,
+) stackRecord {
+	r := stackRecord{
+		NormalizedPath: string(i.AppendNormalizedPath(nil)),
+		DotPath:        string(i.AppendDotPath(nil)),
+		Match:          i.Match(p),
+	}
+	i.ScanStack(func(keyIndex, keyEnd, arrayIndex int) {
+		r.Stack = append(r.Stack, fmt.Sprint(keyIndex, keyEnd, arrayIndex))
+	})
+	for l := 0; l < i.Level(); l++ {
+		f := i.Frame(l)
+		r.Frames = append(r.Frames, fmt.Sprintf("%s %d %d %d %q %d %d",
+			f.ValueType, f.Index, f.KeyIndex, f.KeyIndexEnd,
+			f.Key(), f.ArrayIndex, f.Len,
+		))
+	}
+	return r
+}
+
+func TestStreamParserStack(t *testing.T) {
+	const input = `{"first key":{"a":[1,{"b":[{"cé":` +
+		`"` + `long string value ` + `"},2]},[[3]]],` +
+		`"escaped \"key\"":{"x":{"y":[true,{"z":"deep"}]}}},` +
+		`"k":[{"a":{"b":null}},"tail"]}`
+	p, err := jscan.CompilePatterns(
+		"/first key/a/1/b/*/cé", "/first key/escaped \"key\"/**", "/k/*",
+	)
+	require.NoError(t, err)
+
+	var expect []stackRecord
+	expectErr := jscan.NewParserWithOptions[string](
+		64, jscan.ParserOptions{EndEvents: true},
+	).Scan(input, func(i *jscan.Iterator[string]) (err bool) {
+		if !i.IsEnd() {
+			// Stream parsers count members of objects like parsers
+			// with end events enabled.
+			expect = append(expect, newStackRecord(i, p))
+		}
+		return false
+	})
+	require.False(t, expectErr.IsErr())
+
+	for _, bufSize := range []int{1, 2, 3, 5, 16, 0} {
+		var actual []stackRecord
+		err := jscan.NewStreamParser(strings.NewReader(input), bufSize).Scan(
+			func(i *jscan.Iterator[[]byte]) (err bool) {
+				actual = append(actual, newStackRecord(i, p))
+				return false
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, expect, actual, "bufSize: %d", bufSize)
+
+		actual = actual[:0]
+		e := jscan.ScanSegments(segment(input, bufSize+1),
+			func(i *jscan.Iterator[[]byte]) (err bool) {
+				actual = append(actual, newStackRecord(i, p))
+				return false
+			},
+		)
+		require.False(t, e.IsErr(), "unexpected error: %v", e)
+		require.Equal(t, expect, actual, "segment size: %d", bufSize+1)
+	}
+}
+
+func TestStreamParserSkip(t *testing.T) {
+	input := `[{"a":[1,2,{"b":3}]},{"c":4},[5,[6]],"` +
+		strings.Repeat("x", 64) + `",{"d":[7]}]`
+	for _, bufSize := range []int{1, 3, 16, 0} {
+		var values []string
+		p := jscan.NewStreamParser(strings.NewReader(input), bufSize)
+		err := p.ScanAction(func(i *jscan.Iterator[[]byte]) jscan.Action {
+			values = append(values, string(i.Pointer()))
+			if i.ArrayIndex() == 0 {
+				return jscan.ActionSkipChildren
+			}
+			if i.ArrayIndex() == 2 {
+				// Objects and arrays aren't buffered.
+				require.Empty(t, i.CaptureRaw())
+			}
+			return jscan.ActionContinue
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"", "/0", "/1", "/1/c", "/2", "/3", "/4", "/4/d", "/4/d/0",
+		}, values, "bufSize: %d", bufSize)
+
+		// Skipped values are still validated.
+		p.Reset(strings.NewReader(`[[1,2,]]`))
+		err = p.ScanAction(func(i *jscan.Iterator[[]byte]) jscan.Action {
+			return jscan.ActionSkipChildren
+		})
+		var e jscan.Error[[]byte]
+		require.True(t, errors.As(err, &e), "unexpected error: %v", err)
+		require.Equal(t, jscan.ErrorCodeUnexpectedToken, e.Code)
+		require.Equal(t, 6, e.Index)
+	}
+}
+
+func TestStreamParserCheckpoint(t *testing.T) {
+	const input = ` { "a" : [ 1 , { } , [ ] , { "b/c~" : null } ] ,` +
+		` "d" : { "e" : [ [ true ] ] } , "f" : "long string value" } `
+	record := func(i *jscan.Iterator[string]) string {
+		return fmt.Sprint(i.Pointer(), " ", i.Value())
+	}
+	var expect []string
+	var offsets []int
+	p := jscan.NewParserWithOptions[string](64, jscan.ParserOptions{
+		EndEvents: true,
+	})
+	err := p.Scan(input, func(i *jscan.Iterator[string]) (err bool) {
+		if !i.IsEnd() {
+			expect = append(expect, record(i))
+			offsets = append(offsets, i.Checkpoint().Offset())
+		}
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+
+	for _, bufSize := range []int{1, 3, 16} {
+		for k := range expect {
+			var c jscan.Checkpoint
+			n := 0
+			err := jscan.NewStreamParser(strings.NewReader(input), bufSize).Scan(
+				func(i *jscan.Iterator[[]byte]) (err bool) {
+					if n == k {
+						c = i.Checkpoint()
+						return true
+					}
+					n++
+					return false
+				},
+			)
+			require.Error(t, err)
+			require.Equal(t, offsets[k], c.Offset(), "bufSize %d, value %d", bufSize, k)
+
+			actual := append([]string(nil), expect[:k+1]...)
+			errResume := p.ResumeScan(input, c,
+				func(i *jscan.Iterator[string]) (err bool) {
+					if !i.IsEnd() {
+						actual = append(actual, record(i))
+					}
+					return false
+				})
+			require.False(t, errResume.IsErr(), "unexpected error: %s", errResume)
+			require.Equal(t, expect, actual, "bufSize %d, value %d", bufSize, k)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package jscan
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+	"unsafe"
+
+	"github.com/romshark/jscan/v2/internal/unescape"
+)
+
+var (
+	// ErrNotString is returned by the typed string accessors of Iterator
+	// when the current value isn't a string.
+	ErrNotString = errors.New("value is not a string")
+
+	// ErrInvalidUUID is returned by (*Iterator).UUID when the string value
+	// isn't a UUID in its canonical textual representation.
+	ErrInvalidUUID = errors.New("invalid UUID")
+)
+
+// AppendBase64Decoded decodes the base64 encoded string value using enc,
+// such as base64.StdEncoding or base64.URLEncoding, appends the decoded bytes
+// to dst and returns the extended buffer.
+// Escape sequences (such as "\/" commonly used by encoders) are decoded first.
+// Returns ErrNotString if the value isn't a string and
+// base64.CorruptInputError if it isn't valid base64 in which case
+// dst is returned unchanged.
+func (i *Iterator[S]) AppendBase64Decoded(
+	dst []byte, enc *base64.Encoding,
+) ([]byte, error) {
+	if i.valueType != ValueTypeString {
+		return dst, ErrNotString
+	}
+	var src []byte
+	if i.valueEscaped {
+		i.scratch = unescape.Append(i.scratch[:0], i.stringContent())
+		src = i.scratch
+	} else {
+		src = unsafeBytes(i.stringContent())
+	}
+	l := len(dst)
+	if n := enc.DecodedLen(len(src)); cap(dst)-l < n {
+		b := make([]byte, l, l+n)
+		copy(b, dst)
+		dst = b
+	}
+	n, err := enc.Decode(dst[l:cap(dst)], src)
+	if err != nil {
+		return dst[:l], err
+	}
+	return dst[:l+n], nil
+}
+
+// Time parses the string value as an RFC 3339 timestamp,
+// such as "2006-01-02T15:04:05.999Z" or "2006-01-02T15:04:05+07:00".
+// Escape sequences are decoded first.
+// Returns ErrNotString if the value isn't a string and
+// *time.ParseError if it isn't a valid RFC 3339 timestamp.
+func (i *Iterator[S]) Time() (time.Time, error) {
+	if i.valueType != ValueTypeString {
+		return time.Time{}, ErrNotString
+	}
+	var s string
+	if i.valueEscaped {
+		i.scratch = unescape.Append(i.scratch[:0], i.stringContent())
+		s = unsafeB2S(i.scratch)
+	} else {
+		s = unsafeString(i.stringContent())
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		// The error refers to s which may alias a mutable buffer.
+		_, err = time.Parse(time.RFC3339, string([]byte(s)))
+		return time.Time{}, err
+	}
+	return t, nil
+}
+
+// UUID parses the string value as a UUID in its canonical textual
+// representation xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+// where x is a case-insensitive hexadecimal digit.
+// Escape sequences are decoded first.
+// Returns ErrNotString if the value isn't a string and
+// ErrInvalidUUID if it isn't a valid UUID.
+func (i *Iterator[S]) UUID() (uuid [16]byte, err error) {
+	if i.valueType != ValueTypeString {
+		return uuid, ErrNotString
+	}
+	if i.valueEscaped {
+		i.scratch = unescape.Append(i.scratch[:0], i.stringContent())
+		return parseUUID(i.scratch)
+	}
+	return parseUUID(i.stringContent())
+}
+
+// stringContent returns the string value without the quotation marks.
+func (i *Iterator[S]) stringContent() S {
+	return i.src[i.valueIndex+1 : i.valueIndexEnd-1]
+}
+
+// parseUUID parses the canonical textual representation of a UUID.
+func parseUUID[S ~string | ~[]byte](s S) (uuid [16]byte, err error) {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return uuid, ErrInvalidUUID
+	}
+	for j, x := 0, 0; j < 16; j++ {
+		if x == 8 || x == 13 || x == 18 || x == 23 {
+			x++
+		}
+		hi, lo := lutSX[s[x]], lutSX[s[x+1]]
+		if hi != 2 || lo != 2 {
+			return [16]byte{}, ErrInvalidUUID
+		}
+		uuid[j] = hexValue(s[x])<<4 | hexValue(s[x+1])
+		x += 2
+	}
+	return uuid, nil
+}
+
+// hexValue returns the value of the valid hexadecimal digit c.
+func hexValue(c byte) byte {
+	switch {
+	case c <= '9':
+		return c - '0'
+	case c <= 'F':
+		return c - 'A' + 10
+	}
+	return c - 'a' + 10
+}
+
+// unsafeString converts s to string without copying if possible.
+// The returned string must not be used after s is mutated.
+func unsafeString[S ~string | ~[]byte](s S) string {
+	switch x := any(s).(type) {
+	case string:
+		return x
+	case []byte:
+		return unsafeB2S(x)
+	}
+	return string(s)
+}
+
+// unsafeBytes converts s to []byte without copying if possible.
+// The returned slice must never be mutated.
+func unsafeBytes[S ~string | ~[]byte](s S) []byte {
+	switch x := any(s).(type) {
+	case string:
+		return unsafe.Slice(unsafe.StringData(x), len(x))
+	case []byte:
+		return x
+	}
+	return []byte(s)
+}
+
+// unsafeB2S converts b to string without copying.
+// b must not be mutated while the returned string is in use.
+func unsafeB2S(b []byte) string {
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
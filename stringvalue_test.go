@@ -0,0 +1,154 @@
+package jscan_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendBase64Decoded(t *testing.T) {
+	for _, td := range []struct {
+		input  string
+		enc    *base64.Encoding
+		expect string
+		err    error
+	}{
+		{`""`, base64.StdEncoding, "", nil},
+		{`"aGVsbG8gd29ybGQ="`, base64.StdEncoding, "hello world", nil},
+		{`"aGVsbG8gd29ybGQ"`, base64.RawStdEncoding, "hello world", nil},
+		{`"+/+/"`, base64.StdEncoding, "\xfb\xff\xbf", nil},
+		{`"\/+\/+"`, base64.StdEncoding, "\xff\xef\xfe", nil},
+		{`"-_-_"`, base64.URLEncoding, "\xfb\xff\xbf", nil},
+		{`"\u0061GVsbG8="`, base64.StdEncoding, "hello", nil},
+		{`"-_-_"`, base64.StdEncoding, "", base64.CorruptInputError(0)},
+		{`"aGVsbG8"`, base64.StdEncoding, "", base64.CorruptInputError(4)},
+		{`42`, base64.StdEncoding, "", jscan.ErrNotString},
+	} {
+		t.Run(td.input, func(t *testing.T) {
+			testAppendBase64Decoded(t, td.input, td.enc, td.expect, td.err)
+			testAppendBase64Decoded(t, []byte(td.input), td.enc, td.expect, td.err)
+		})
+	}
+}
+
+func testAppendBase64Decoded[S ~string | ~[]byte](
+	t *testing.T, input S, enc *base64.Encoding, expect string, expectErr error,
+) {
+	err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+		for _, dst := range [][]byte{nil, []byte("prefix:"), make([]byte, 1, 64)} {
+			prefix := string(dst)
+			actual, err := i.AppendBase64Decoded(dst, enc)
+			require.Equal(t, expectErr, err)
+			if err != nil {
+				require.Equal(t, prefix, string(actual))
+				continue
+			}
+			require.Equal(t, prefix+expect, string(actual))
+		}
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+}
+
+func TestTime(t *testing.T) {
+	for _, td := range []struct {
+		input  string
+		expect time.Time
+		err    bool
+	}{
+		{
+			`"2006-01-02T15:04:05Z"`,
+			time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), false,
+		},
+		{
+			`"2006-01-02T15:04:05.123456789Z"`,
+			time.Date(2006, 1, 2, 15, 4, 5, 123456789, time.UTC), false,
+		},
+		{
+			`"2006-01-02T15:04:05+07:00"`,
+			time.Date(2006, 1, 2, 8, 4, 5, 0, time.UTC), false,
+		},
+		{
+			`"2006-01-02\u005415:04:05Z"`,
+			time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), false,
+		},
+		{`"2006-01-02"`, time.Time{}, true},
+		{`"2006-01-02 15:04:05Z"`, time.Time{}, true},
+		{`"2006-13-02T15:04:05Z"`, time.Time{}, true},
+		{`""`, time.Time{}, true},
+	} {
+		t.Run(td.input, func(t *testing.T) {
+			testTime(t, td.input, td.expect, td.err)
+			testTime(t, []byte(td.input), td.expect, td.err)
+		})
+	}
+
+	err := jscan.Scan(`null`, func(i *jscan.Iterator[string]) (err bool) {
+		_, err2 := i.Time()
+		require.Equal(t, jscan.ErrNotString, err2)
+		return false
+	})
+	require.False(t, err.IsErr())
+}
+
+func testTime[S ~string | ~[]byte](
+	t *testing.T, input S, expect time.Time, expectErr bool,
+) {
+	err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+		actual, err2 := i.Time()
+		if expectErr {
+			var perr *time.ParseError
+			require.ErrorAs(t, err2, &perr)
+			return false
+		}
+		require.NoError(t, err2)
+		require.True(t, expect.Equal(actual), "expected %s, got %s", expect, actual)
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+}
+
+func TestUUID(t *testing.T) {
+	expect := [16]byte{
+		0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3,
+		0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00,
+	}
+	for _, td := range []struct {
+		input  string
+		expect [16]byte
+		err    error
+	}{
+		{`"123e4567-e89b-12d3-a456-426614174000"`, expect, nil},
+		{`"123E4567-E89B-12D3-A456-426614174000"`, expect, nil},
+		{`"123e\u0034567-e89b-12d3-a456-426614174000"`, expect, nil},
+		{`"00000000-0000-0000-0000-000000000000"`, [16]byte{}, nil},
+		{`"123e4567e89b12d3a456426614174000"`, [16]byte{}, jscan.ErrInvalidUUID},
+		{`"123e4567-e89b-12d3-a456-42661417400"`, [16]byte{}, jscan.ErrInvalidUUID},
+		{`"123e4567-e89b-12d3-a456-4266141740000"`, [16]byte{}, jscan.ErrInvalidUUID},
+		{`"123e4567-e89b-12d3-a456_426614174000"`, [16]byte{}, jscan.ErrInvalidUUID},
+		{`"123g4567-e89b-12d3-a456-426614174000"`, [16]byte{}, jscan.ErrInvalidUUID},
+		{`"{23e4567-e89b-12d3-a456-426614174000"`, [16]byte{}, jscan.ErrInvalidUUID},
+		{`""`, [16]byte{}, jscan.ErrInvalidUUID},
+		{`1`, [16]byte{}, jscan.ErrNotString},
+	} {
+		t.Run(td.input, func(t *testing.T) {
+			testUUID(t, td.input, td.expect, td.err)
+			testUUID(t, []byte(td.input), td.expect, td.err)
+		})
+	}
+}
+
+func testUUID[S ~string | ~[]byte](
+	t *testing.T, input S, expect [16]byte, expectErr error,
+) {
+	err := jscan.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+		actual, err2 := i.UUID()
+		require.Equal(t, expectErr, err2)
+		require.Equal(t, expect, actual)
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+}
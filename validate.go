@@ -102,11 +102,47 @@ func NewValidator[S ~string | ~[]byte](preallocStackFrames int) *Validator[S] {
 	}
 }
 
+// ValidatorOptions are options for the validator.
+type ValidatorOptions struct {
+	// Numbers, if not zero, makes the validator reject all numbers that
+	// aren't exactly representable by the given type regardless of their
+	// notation with ErrorCodeNumberNotRepresentable (see (Decimal).Fits).
+	// Validators with a number type constraint are less efficient.
+	Numbers NumberType
+
+	// Progress, if not nil, is called periodically by
+	// (*Validator).ValidateContext with the number of bytes of the source
+	// consumed so far, and once more with the length of the source
+	// when validation succeeds.
+	Progress func(consumed int)
+}
+
+// NewValidatorWithOptions creates a new reusable validator instance.
+// See NewValidator for more details.
+func NewValidatorWithOptions[S ~string | ~[]byte](
+	preallocStackFrames int, o ValidatorOptions,
+) *Validator[S] {
+	v := NewValidator[S](preallocStackFrames)
+	v.numbers, v.progress = o.Numbers, o.Progress
+	return v
+}
+
 // Validator is a reusable validator instance.
 // The validator is more efficient than the parser at JSON validation.
 // A validator instance can be more efficient than global Valid, Validate and ValidateOne
 // function calls due to potential stack frame allocation avoidance.
-type Validator[S ~string | ~[]byte] struct{ stack []stackNodeType }
+type Validator[S ~string | ~[]byte] struct {
+	stack    []stackNodeType
+	numbers  NumberType
+	progress func(consumed int)
+
+	// i is used for validation with a number type constraint.
+	i *Iterator[S]
+
+	// buf and window are used by ValidateReader.
+	buf    []byte
+	window windowValidator
+}
 
 // Valid returns true if s is a valid JSON value, otherwise returns false.
 func (v *Validator[S]) Valid(s S) bool {
@@ -118,13 +154,13 @@ func (v *Validator[S]) Valid(s S) bool {
 // In case of an error trailing will be a substring of s cut up until the index
 // where the error was encountered.
 func (v *Validator[S]) ValidateOne(s S) (trailing S, err Error[S]) {
-	return validate(v.stack, s)
+	return v.validateOne(s)
 }
 
 // Validate returns an error if s is invalid JSON,
 // otherwise returns a zero value of Error[S].
 func (v *Validator[S]) Validate(s S) Error[S] {
-	t, err := validate(v.stack, s)
+	t, err := v.validateOne(s)
 	if err.IsErr() {
 		return err
 	}
@@ -139,6 +175,26 @@ func (v *Validator[S]) Validate(s S) Error[S] {
 	return Error[S]{}
 }
 
+// validateOne validates one JSON value from s
+// taking the options of v into account.
+func (v *Validator[S]) validateOne(s S) (S, Error[S]) {
+	if v.numbers == 0 {
+		return validate(v.stack, s)
+	}
+	if v.i == nil {
+		v.i = newIterator[S]()
+	}
+	reset(v.i)
+	v.i.src = s
+	t, err := scan(v.i, func(i *Iterator[S]) (err bool) {
+		return i.valueType == ValueTypeNumber && !i.numberFits(v.numbers)
+	})
+	if err.Code == ErrorCodeCallback {
+		err.Code = ErrorCodeNumberNotRepresentable
+	}
+	return t, err
+}
+
 // validate returns the remainder of i.src and an error if any is encountered.
 func validate[S ~string | ~[]byte](st []stackNodeType, s S) (S, Error[S]) {
 	var (
@@ -601,3 +657,354 @@ AFTER_VALUE:
 	}
 	return s, getError(ErrorCodeUnexpectedToken, src, s)
 }
+
+// windowState is the state of a windowValidator between two windows.
+type windowState int8
+
+const (
+	windowValue           windowState = iota // Expecting a value.
+	windowValueOrArrayEnd                    // Expecting a value or ']'.
+	windowKeyOrObjectEnd                     // Expecting a key or '}'.
+	windowKey                                // Expecting a key.
+	windowColon                              // Expecting ':' after a key.
+	windowAfterValue                         // Expecting ',', '}' or ']'.
+	windowString                             // Within a string value.
+	windowKeyString                          // Within a key.
+	windowNumber                             // Within a number.
+	windowLiteral                            // Within true, false or null.
+	windowDone                               // After the value.
+)
+
+// numberState is the state of a number read byte by byte.
+type numberState int8
+
+const (
+	numberStart    numberState = iota // Before the number.
+	numberSign                        // After the minus sign.
+	numberZero                        // After the leading zero.
+	numberInteger                     // Within the integer part.
+	numberDot                         // After the decimal point.
+	numberFraction                    // Within the fraction.
+	numberE                           // After the exponent marker.
+	numberExpSign                     // After the sign of the exponent.
+	numberExponent                    // Within the exponent.
+)
+
+// next returns the state of the number after c and
+// false if c doesn't continue the number.
+func (n numberState) next(c byte) (numberState, bool) {
+	digit := c >= '0' && c <= '9'
+	switch n {
+	case numberStart, numberSign:
+		switch {
+		case c == '-' && n == numberStart:
+			return numberSign, true
+		case c == '0':
+			return numberZero, true
+		case digit:
+			return numberInteger, true
+		}
+	case numberZero, numberInteger:
+		switch {
+		case digit && n == numberInteger:
+			return numberInteger, true
+		case c == '.':
+			return numberDot, true
+		case c == 'e' || c == 'E':
+			return numberE, true
+		}
+	case numberDot, numberFraction:
+		switch {
+		case digit:
+			return numberFraction, true
+		case (c == 'e' || c == 'E') && n == numberFraction:
+			return numberE, true
+		}
+	case numberE, numberExpSign, numberExponent:
+		switch {
+		case digit:
+			return numberExponent, true
+		case (c == '-' || c == '+') && n == numberE:
+			return numberExpSign, true
+		}
+	}
+	return n, false
+}
+
+// complete returns true if the number is valid when it ends in state n.
+func (n numberState) complete() bool {
+	return n == numberZero || n == numberInteger ||
+		n == numberFraction || n == numberExponent
+}
+
+// windowValidator is a JSON validator that validates its input in
+// consecutive windows and doesn't need to retain any previous window.
+// It's a resumable variant of validate that's used where the input must
+// be checked periodically or isn't available all at once.
+type windowValidator struct {
+	st    []stackNodeType
+	state windowState
+
+	// one makes the validator stop after the first value
+	// instead of checking the trailing whitespace.
+	one bool
+
+	// start is the index in the input of the escape sequence,
+	// number or literal being read.
+	start int
+
+	// escape is the number of bytes read of the escape sequence
+	// being read, or 0 if there is none.
+	escape int
+
+	// number is the state of the number being read.
+	number numberState
+
+	// literal is the remainder of the literal being read.
+	literal string
+
+	// numbers, if not zero, makes the validator reject numbers that
+	// aren't exactly representable by it. Numbers are then checked
+	// in the window they end in, which must contain the entire number.
+	numbers NumberType
+}
+
+// reset resets v for a new input reusing st for the stack.
+func (v *windowValidator) reset(st []stackNodeType, one bool, numbers NumberType) {
+	*v = windowValidator{st: st[:0], one: one, numbers: numbers}
+}
+
+// pending returns the index in the input of the escape sequence, number or
+// literal being read and false if there is none.
+func (v *windowValidator) pending() (start int, ok bool) {
+	switch v.state {
+	case windowString, windowKeyString:
+		return v.start, v.escape > 0
+	case windowNumber, windowLiteral:
+		return v.start, true
+	}
+	return 0, false
+}
+
+// validateWindow validates w[j:] continuing the validation of
+// the preceding windows where offset is the index of w in the input and
+// eof indicates that w ends the input. In one mode validateWindow returns
+// the index in w immediately after the value if it ends within w,
+// otherwise it returns len(w). Validation errors refer to w and to
+// the same indexes in the input validate reports.
+func validateWindow[S ~string | ~[]byte](
+	v *windowValidator, w S, j, offset int, eof bool,
+) (int, Error[S]) {
+	for {
+		switch v.state {
+		case windowString, windowKeyString:
+			for v.escape > 0 && j < len(w) {
+				c := w[j]
+				switch {
+				case v.escape == 1 && lutEscape[c] == 1:
+					v.escape = 0
+				case v.escape == 1 && c == 'u', v.escape > 1 && lutSX[c] == 2:
+					if v.escape++; v.escape == len(`\uXXXX`) {
+						v.escape = 0
+					}
+				default:
+					return j, windowError(w, v.start, offset, ErrorCodeInvalidEscape)
+				}
+				j++
+			}
+			for j < len(w) && lutStr[w[j]] == 0 {
+				j++
+			}
+			if j == len(w) {
+				if !eof {
+					return j, Error[S]{}
+				}
+				if v.escape > 1 {
+					return j, windowError(w, v.start, offset, ErrorCodeInvalidEscape)
+				}
+				return j, windowError(w, offset+j, offset, ErrorCodeUnexpectedEOF)
+			}
+			switch w[j] {
+			case '"':
+				if v.state == windowKeyString {
+					v.state = windowColon
+				} else {
+					v.state = windowAfterValue
+				}
+			case '\\':
+				v.start, v.escape = offset+j, 1
+			default:
+				return j, windowError(w, offset+j, offset, ErrorCodeIllegalControlChar)
+			}
+			j++
+			continue
+
+		case windowNumber:
+			for ; j < len(w); j++ {
+				n, ok := v.number.next(w[j])
+				if !ok {
+					break
+				}
+				v.number = n
+			}
+			if j == len(w) && !eof {
+				return j, Error[S]{}
+			}
+			if !v.number.complete() {
+				return j, windowError(w, v.start, offset, ErrorCodeMalformedNumber)
+			}
+			if v.numbers != 0 && !numberFits(
+				w[v.start-offset:j],
+				v.number == numberZero || v.number == numberInteger, v.numbers,
+			) {
+				return j, windowError(w, v.start, offset, ErrorCodeNumberNotRepresentable)
+			}
+			v.state = windowAfterValue
+			continue
+
+		case windowLiteral:
+			n := min(len(v.literal), len(w)-j)
+			if string(w[j:j+n]) != v.literal[:n] {
+				return j, windowError(w, v.start, offset, ErrorCodeUnexpectedToken)
+			}
+			j, v.literal = j+n, v.literal[n:]
+			if len(v.literal) > 0 {
+				if !eof {
+					return j, Error[S]{}
+				}
+				return j, windowError(w, v.start, offset, ErrorCodeUnexpectedToken)
+			}
+			v.state = windowAfterValue
+			continue
+
+		case windowAfterValue:
+			if len(v.st) == 0 {
+				v.state = windowDone
+				continue
+			}
+
+		case windowDone:
+			if v.one {
+				return j, Error[S]{}
+			}
+		}
+
+		// Expecting a structural character or the start of a value.
+		if j < len(w) && w[j] <= ' ' {
+			t, ctrlChar := strfind.EndOfWhitespaceSeq(w[j:])
+			if j = len(w) - len(t); ctrlChar {
+				return j, windowError(w, offset+j, offset, ErrorCodeIllegalControlChar)
+			}
+		}
+		if j == len(w) {
+			if !eof || v.state == windowDone {
+				return j, Error[S]{}
+			}
+			return j, windowError(w, offset+j, offset, ErrorCodeUnexpectedEOF)
+		}
+
+		c := w[j]
+		switch v.state {
+		case windowValue, windowValueOrArrayEnd:
+			switch c {
+			case '{':
+				v.st = append(v.st, stackNodeTypeObject)
+				v.state = windowKeyOrObjectEnd
+				j++
+				continue
+			case '[':
+				v.st = append(v.st, stackNodeTypeArray)
+				v.state = windowValueOrArrayEnd
+				j++
+				continue
+			case '"':
+				v.state = windowString
+				j++
+				continue
+			case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+				if t, rc := jsonnum.ReadNumber(w[j:]); len(t) > 0 || eof {
+					if rc == jsonnum.ReturnCodeErr {
+						return j, windowError(w, offset+j, offset, ErrorCodeMalformedNumber)
+					}
+					if v.numbers != 0 && !numberFits(
+						w[j:len(w)-len(t)], rc == jsonnum.ReturnCodeInteger, v.numbers,
+					) {
+						return j, windowError(
+							w, offset+j, offset, ErrorCodeNumberNotRepresentable,
+						)
+					}
+					j, v.state = len(w)-len(t), windowAfterValue
+					continue
+				}
+				v.state, v.start, v.number = windowNumber, offset+j, numberStart
+				continue
+			case 't':
+				v.state, v.start, v.literal = windowLiteral, offset+j, "true"
+				continue
+			case 'f':
+				v.state, v.start, v.literal = windowLiteral, offset+j, "false"
+				continue
+			case 'n':
+				v.state, v.start, v.literal = windowLiteral, offset+j, "null"
+				continue
+			case ']':
+				if v.state == windowValueOrArrayEnd {
+					v.st = v.st[:len(v.st)-1]
+					v.state = windowAfterValue
+					j++
+					continue
+				}
+			}
+
+		case windowKeyOrObjectEnd, windowKey:
+			if c == '"' {
+				v.state = windowKeyString
+				j++
+				continue
+			}
+			if c == '}' && v.state == windowKeyOrObjectEnd {
+				v.st = v.st[:len(v.st)-1]
+				v.state = windowAfterValue
+				j++
+				continue
+			}
+
+		case windowColon:
+			if c == ':' {
+				v.state = windowValue
+				j++
+				continue
+			}
+
+		case windowAfterValue:
+			top := v.st[len(v.st)-1]
+			switch {
+			case c == ',' && top == stackNodeTypeObject:
+				v.state = windowKey
+				j++
+				continue
+			case c == ',':
+				v.state = windowValue
+				j++
+				continue
+			case c == '}' && top == stackNodeTypeObject,
+				c == ']' && top == stackNodeTypeArray:
+				v.st = v.st[:len(v.st)-1]
+				j++
+				continue
+			}
+		}
+		if c < 0x20 {
+			return j, windowError(w, offset+j, offset, ErrorCodeIllegalControlChar)
+		}
+		return j, windowError(w, offset+j, offset, ErrorCodeUnexpectedToken)
+	}
+}
+
+// windowError returns an error at index in the input for the window w
+// starting at offset.
+func windowError[S ~string | ~[]byte](
+	w S, index, offset int, code ErrorCode,
+) Error[S] {
+	return Error[S]{Src: w, Index: index, Code: code, offset: offset}
+}
@@ -0,0 +1,134 @@
+package jscan
+
+import "github.com/romshark/jscan/v2/internal/strfind"
+
+// Visitor is a SAX-style receiver of typed events.
+// Every method returns true to stop scanning with ErrorCodeCallback,
+// otherwise returns false to resume scanning.
+//
+// level is the number of objects and arrays the value is nested in
+// (see (*Iterator).Level) and index is the start index of the value
+// or key in the source.
+// Keys and string values are passed as they appear in the source
+// including the surrounding quotation marks and escape sequences.
+//
+// WARNING: Don't alias key or value after the method returns
+// if the source is mutable!
+type Visitor[S ~string | ~[]byte] interface {
+	// OnObjectStart is called when an object is encountered.
+	OnObjectStart(level, index int) (err bool)
+
+	// OnObjectEnd is called when the end of an object is reached.
+	// indexEnd is the index in the source immediately after the closing
+	// brace and members is the number of members of the object.
+	OnObjectEnd(level, index, indexEnd, members int) (err bool)
+
+	// OnArrayStart is called when an array is encountered.
+	OnArrayStart(level, index int) (err bool)
+
+	// OnArrayEnd is called when the end of an array is reached.
+	// indexEnd is the index in the source immediately after the closing
+	// bracket and elements is the number of elements of the array.
+	OnArrayEnd(level, index, indexEnd, elements int) (err bool)
+
+	// OnKey is called before the value of every object member.
+	// level is the level of the member value.
+	OnKey(level, index int, key S) (err bool)
+
+	// OnString is called for every string value.
+	OnString(level, index int, value S) (err bool)
+
+	// OnNumber is called for every number value.
+	OnNumber(level, index int, value S) (err bool)
+
+	// OnBool is called for every true and false value.
+	OnBool(level, index int, value bool) (err bool)
+
+	// OnNull is called for every null value.
+	OnNull(level, index int) (err bool)
+}
+
+// ScanVisitor calls the methods of v for every encountered value and
+// for the end of every object and array.
+//
+// Unlike (*Parser).ScanVisitor this function will take an iterator instance
+// from a global iterator pool and can therefore be less efficient.
+// Consider reusing a Parser instance instead.
+func ScanVisitor[S ~string | ~[]byte](s S, v Visitor[S]) Error[S] {
+	var i *Iterator[S]
+	switch any(s).(type) {
+	case string:
+		x := iteratorPoolString.Get()
+		defer iteratorPoolString.Put(x)
+		i = x.(*Iterator[S])
+	case []byte:
+		x := iteratorPoolBytes.Get()
+		defer iteratorPoolBytes.Put(x)
+		i = x.(*Iterator[S])
+	default:
+		i = newIterator[S]()
+	}
+	return scanVisitor(i, s, v)
+}
+
+// ScanVisitor calls the methods of v for every encountered value and
+// for the end of every object and array.
+// End events are reported to v regardless of ParserOptions.EndEvents.
+func (p *Parser[S]) ScanVisitor(s S, v Visitor[S]) Error[S] {
+	return scanVisitor(p.i, s, v)
+}
+
+// scanVisitor scans the entire source s calling the methods of v
+// for the values and end events reported by scan.
+func scanVisitor[S ~string | ~[]byte](
+	i *Iterator[S], s S, v Visitor[S],
+) Error[S] {
+	reset(i)
+	i.src = s
+	endEvents := i.endEvents
+	i.endEvents = true
+	defer func() { i.endEvents = endEvents }()
+
+	var stoppedAtKey bool
+	t, err := scan(i, func(i *Iterator[S]) (err bool) {
+		level := len(i.stack)
+		if i.end {
+			if i.valueType == ValueTypeObject {
+				return v.OnObjectEnd(level, i.valueIndex, i.valueIndexEnd, i.len)
+			}
+			return v.OnArrayEnd(level, i.valueIndex, i.valueIndexEnd, i.len)
+		}
+		if i.keyIndex != -1 &&
+			v.OnKey(level, i.keyIndex, i.src[i.keyIndex:i.keyIndexEnd]) {
+			stoppedAtKey = true
+			return true
+		}
+		switch i.valueType {
+		case ValueTypeObject:
+			return v.OnObjectStart(level, i.valueIndex)
+		case ValueTypeArray:
+			return v.OnArrayStart(level, i.valueIndex)
+		case ValueTypeString:
+			return v.OnString(level, i.valueIndex, i.src[i.valueIndex:i.valueIndexEnd])
+		case ValueTypeNumber:
+			return v.OnNumber(level, i.valueIndex, i.src[i.valueIndex:i.valueIndexEnd])
+		case ValueTypeTrue, ValueTypeFalse:
+			return v.OnBool(level, i.valueIndex, i.valueType == ValueTypeTrue)
+		}
+		return v.OnNull(level, i.valueIndex)
+	})
+	if err.IsErr() {
+		if stoppedAtKey {
+			err.Index = i.keyIndex
+		}
+		return err
+	}
+	var illegalChar bool
+	if t, illegalChar = strfind.EndOfWhitespaceSeq(t); illegalChar {
+		return getError(ErrorCodeIllegalControlChar, s, t)
+	}
+	if len(t) > 0 {
+		return getError(ErrorCodeUnexpectedToken, s, t)
+	}
+	return Error[S]{}
+}
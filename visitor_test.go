@@ -0,0 +1,198 @@
+package jscan_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/romshark/jscan/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// encoder is a visitor re-encoding the visited JSON in compact form.
+type encoder[S ~string | ~[]byte] struct {
+	b      []byte
+	counts []int
+	stopAt string
+}
+
+func (e *encoder[S]) comma() {
+	if len(e.b) > 0 {
+		switch e.b[len(e.b)-1] {
+		case '{', '[', ':':
+			return
+		}
+		e.b = append(e.b, ',')
+	}
+}
+
+func (e *encoder[S]) write(s string) bool {
+	e.comma()
+	e.b = append(e.b, s...)
+	return s == e.stopAt
+}
+
+func (e *encoder[S]) OnObjectStart(int, int) bool { return e.write("{") }
+func (e *encoder[S]) OnArrayStart(int, int) bool  { return e.write("[") }
+func (e *encoder[S]) OnNull(int, int) bool        { return e.write("null") }
+
+func (e *encoder[S]) OnBool(_, _ int, v bool) bool {
+	return e.write(strconv.FormatBool(v))
+}
+
+func (e *encoder[S]) OnString(_, _ int, v S) bool { return e.write(string(v)) }
+func (e *encoder[S]) OnNumber(_, _ int, v S) bool { return e.write(string(v)) }
+
+func (e *encoder[S]) OnKey(_, _ int, k S) bool {
+	e.comma()
+	e.b = append(e.b, k...)
+	e.b = append(e.b, ':')
+	return false
+}
+
+func (e *encoder[S]) OnObjectEnd(_, _, _, members int) bool {
+	e.b = append(e.b, '}')
+	e.counts = append(e.counts, members)
+	return false
+}
+
+func (e *encoder[S]) OnArrayEnd(_, _, _, elements int) bool {
+	e.b = append(e.b, ']')
+	e.counts = append(e.counts, elements)
+	return false
+}
+
+func TestScanVisitor(t *testing.T) {
+	const input = ` { "a" : [ 1 , { } , [ ] ] , "b\"" : { "c" : [ true , false , null ] } ,` +
+		` "d" : "A" , "e" : -1.5e3 } `
+	testScanVisitor(t, string(input))
+	testScanVisitor(t, []byte(input))
+}
+
+func testScanVisitor[S ~string | ~[]byte](t *testing.T, input S) {
+	const expect = `{"a":[1,{},[]],"b\"":{"c":[true,false,null]},"d":"A","e":-1.5e3}`
+	expectCounts := []int{0, 0, 3, 3, 1, 4}
+
+	t.Run(testDataType(input), func(t *testing.T) {
+		t.Run("ScanVisitor", func(t *testing.T) {
+			e := new(encoder[S])
+			err := jscan.ScanVisitor[S](input, e)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, expect, string(e.b))
+			require.Equal(t, expectCounts, e.counts)
+		})
+
+		t.Run("ParserScanVisitor", func(t *testing.T) {
+			p := jscan.NewParser[S](64)
+			e := new(encoder[S])
+			err := p.ScanVisitor(input, e)
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+			require.Equal(t, expect, string(e.b))
+			require.Equal(t, expectCounts, e.counts)
+
+			// End events must not leak into regular scans.
+			err = p.Scan(input, func(i *jscan.Iterator[S]) (err bool) {
+				require.False(t, i.IsEnd())
+				return false
+			})
+			require.False(t, err.IsErr(), "unexpected error: %s", err)
+		})
+
+		t.Run("Stop", func(t *testing.T) {
+			e := &encoder[S]{stopAt: "true"}
+			err := jscan.ScanVisitor[S](input, e)
+			require.Equal(t, jscan.ErrorCodeCallback, err.Code)
+			require.Equal(t, `{"a":[1,{},[]],"b\"":{"c":[true`, string(e.b))
+		})
+
+		t.Run("SyntaxError", func(t *testing.T) {
+			e := new(encoder[S])
+			err := jscan.ScanVisitor[S](S(`[1,2] 3`), e)
+			require.Equal(t, jscan.ErrorCodeUnexpectedToken, err.Code)
+			require.Equal(t, len(`[1,2] `), err.Index)
+		})
+	})
+}
+
+// positionRecorder is a visitor recording the position of every event.
+type positionRecorder[S ~string | ~[]byte] struct{ events []string }
+
+func (r *positionRecorder[S]) add(f string, a ...any) bool {
+	r.events = append(r.events, fmt.Sprintf(f, a...))
+	return false
+}
+
+func (r *positionRecorder[S]) OnObjectStart(level, index int) bool {
+	return r.add("object %d %d", level, index)
+}
+
+func (r *positionRecorder[S]) OnArrayStart(level, index int) bool {
+	return r.add("array %d %d", level, index)
+}
+
+func (r *positionRecorder[S]) OnObjectEnd(level, index, end, n int) bool {
+	return r.add("object end %d %d %d %d", level, index, end, n)
+}
+
+func (r *positionRecorder[S]) OnArrayEnd(level, index, end, n int) bool {
+	return r.add("array end %d %d %d %d", level, index, end, n)
+}
+
+func (r *positionRecorder[S]) OnKey(level, index int, k S) bool {
+	return r.add("key %d %d %s", level, index, k)
+}
+
+func (r *positionRecorder[S]) OnString(level, index int, v S) bool {
+	return r.add("string %d %d %s", level, index, v)
+}
+
+func (r *positionRecorder[S]) OnNumber(level, index int, v S) bool {
+	return r.add("number %d %d %s", level, index, v)
+}
+
+func (r *positionRecorder[S]) OnBool(level, index int, v bool) bool {
+	return r.add("%t %d %d", v, level, index)
+}
+
+func (r *positionRecorder[S]) OnNull(level, index int) bool {
+	return r.add("null %d %d", level, index)
+}
+
+func TestScanVisitorPositions(t *testing.T) {
+	const input = ` { "a" : [ 1 , { } , [ ] ] , "b\"" : { "c" : [ true , false , null ] } ,` +
+		` "d" : "A" , "e" : -1.5e3 } `
+
+	// Positions reported by the visitor must be equal to
+	// the positions reported by the iterator.
+	var expect []string
+	p := jscan.NewParserWithOptions[string](64, jscan.ParserOptions{
+		EndEvents: true,
+	})
+	err := p.Scan(input, func(i *jscan.Iterator[string]) (err bool) {
+		if i.IsEnd() {
+			expect = append(expect, fmt.Sprintf("%s end %d %d %d %d",
+				i.ValueType(), i.Level(), i.ValueIndex(), i.ValueIndexEnd(), i.Len()))
+			return false
+		}
+		if i.KeyIndex() != -1 {
+			expect = append(expect, fmt.Sprintf("key %d %d %s",
+				i.Level(), i.KeyIndex(), i.Key()))
+		}
+		switch i.ValueType() {
+		case jscan.ValueTypeObject, jscan.ValueTypeArray, jscan.ValueTypeNull,
+			jscan.ValueTypeTrue, jscan.ValueTypeFalse:
+			expect = append(expect, fmt.Sprintf("%s %d %d",
+				i.ValueType(), i.Level(), i.ValueIndex()))
+		default:
+			expect = append(expect, fmt.Sprintf("%s %d %d %s",
+				i.ValueType(), i.Level(), i.ValueIndex(), i.Value()))
+		}
+		return false
+	})
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+
+	r := new(positionRecorder[string])
+	err = jscan.ScanVisitor[string](input, r)
+	require.False(t, err.IsErr(), "unexpected error: %s", err)
+	require.Equal(t, expect, r.events)
+}